@@ -0,0 +1,380 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/hsr-tools/backend/internal/config"
+	"github.com/hsr-tools/backend/internal/database"
+	"github.com/hsr-tools/backend/internal/jobs"
+	"github.com/hsr-tools/backend/internal/models"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/term"
+	"gorm.io/gorm"
+)
+
+var migrateUpCommand = &command{
+	name:    "up",
+	summary: "Apply pending migrations",
+	flags: func(fs *flag.FlagSet, cfg *config.Config) {
+		fs.IntVar(&migrateSteps, "steps", 0, "number of migrations to apply (0 applies all)")
+		databaseFlag(fs, cfg)
+	},
+	run: func(cfg *config.Config, args []string) error {
+		if err := connect(cfg); err != nil {
+			return err
+		}
+		defer database.Close()
+
+		if err := database.MigrateUp(migrateSteps); err != nil {
+			return fmt.Errorf("migration failed: %w", err)
+		}
+		log.Println("✅ Migration completed successfully!")
+		return nil
+	},
+}
+
+var migrateCommand = &command{
+	name:    "migrate",
+	summary: "Apply, roll back, list or create schema migrations",
+	commands: []*command{
+		migrateUpCommand,
+		{
+			name:    "down",
+			summary: "Roll back the latest migrations",
+			help:    "Runs the down migrations, which usually drop tables or columns and the data in them.",
+			flags: func(fs *flag.FlagSet, cfg *config.Config) {
+				fs.IntVar(&migrateSteps, "steps", 1, "number of migrations to roll back")
+				fs.BoolVar(&assumeYes, "yes", false, "don't ask for confirmation")
+				databaseFlag(fs, cfg)
+			},
+			run: func(cfg *config.Config, args []string) error {
+				if err := confirm(assumeYes, "This will roll back %d migration(s) in %s", migrateSteps, databaseName(cfg)); err != nil {
+					return err
+				}
+				if err := connect(cfg); err != nil {
+					return err
+				}
+				defer database.Close()
+
+				if err := database.MigrateDown(migrateSteps); err != nil {
+					return fmt.Errorf("rollback failed: %w", err)
+				}
+				log.Println("✅ Rollback completed successfully!")
+				return nil
+			},
+		},
+		{
+			name:    "status",
+			summary: "List migrations and whether they have been applied",
+			flags: func(fs *flag.FlagSet, cfg *config.Config) {
+				databaseFlag(fs, cfg)
+			},
+			run: func(cfg *config.Config, args []string) error {
+				if err := connect(cfg); err != nil {
+					return err
+				}
+				defer database.Close()
+
+				statuses, err := database.MigrationStatuses(context.Background())
+				if err != nil {
+					return fmt.Errorf("failed to read migration status: %w", err)
+				}
+				fmt.Printf("%-16s %-10s %-20s %s\n", "VERSION", "STATE", "APPLIED AT", "NAME")
+				for _, s := range statuses {
+					appliedAt := "-"
+					if s.AppliedAt != nil {
+						appliedAt = s.AppliedAt.UTC().Format("2006-01-02 15:04:05")
+					}
+					fmt.Printf("%-16d %-10s %-20s %s\n", s.Version, s.State(), appliedAt, s.Name)
+				}
+				return nil
+			},
+		},
+		{
+			name:    "create",
+			args:    "<name>",
+			summary: "Create empty up and down files for a new migration",
+			help:    "Only writes files, so no database connection is needed.",
+			flags: func(fs *flag.FlagSet, cfg *config.Config) {
+				fs.StringVar(&migrationsDir, "dir", "internal/database/migrations", "directory to write the migration files to")
+			},
+			run: func(cfg *config.Config, args []string) error {
+				if len(args) != 1 {
+					return errors.New("usage: migrate create [-dir path] <name>")
+				}
+				paths, err := database.CreateMigration(migrationsDir, args[0])
+				if err != nil {
+					return fmt.Errorf("failed to create migration: %w", err)
+				}
+				for _, path := range paths {
+					log.Printf("   ✓ Created %s", path)
+				}
+				return nil
+			},
+		},
+	},
+	fallback: migrateUpCommand,
+}
+
+var seedCommand = &command{
+	name:    "seed",
+	summary: "Apply pending migrations and load the game data files",
+	help: `Validates every data file, then applies them in one transaction. Use
+-dry-run to see what would change; it doesn't migrate, so it fails while
+migrations are pending. With -prune, rows seed wrote whose
+entries were removed from the data files are deleted too, which asks for
+confirmation. Rows added through the admin API are never pruned.`,
+	flags: func(fs *flag.FlagSet, cfg *config.Config) {
+		fs.BoolVar(&seedOptions.DryRun, "dry-run", false, "report what would change without writing anything")
+		fs.BoolVar(&seedOptions.Prune, "prune", false, "delete rows that are no longer in the data files")
+		fs.BoolVar(&assumeYes, "yes", false, "don't ask for confirmation")
+		dataPathFlag(fs, cfg)
+		databaseFlag(fs, cfg)
+	},
+	run: func(cfg *config.Config, args []string) error {
+		if seedOptions.Prune && !seedOptions.DryRun {
+			if err := confirm(assumeYes, "This will delete game data missing from the data files in %s", databaseName(cfg)); err != nil {
+				return err
+			}
+		}
+		if err := connect(cfg); err != nil {
+			return err
+		}
+		defer database.Close()
+
+		// First run migrations. A dry run writes nothing, so it can only
+		// compare against a schema that is already up to date.
+		if seedOptions.DryRun {
+			pending, err := database.PendingMigrations(context.Background())
+			if err != nil {
+				return fmt.Errorf("failed to read migration status: %w", err)
+			}
+			if pending > 0 {
+				return fmt.Errorf("%d migration(s) pending; run \"migrate\" before a dry run", pending)
+			}
+		} else if err := database.Migrate(); err != nil {
+			return fmt.Errorf("migration failed: %w", err)
+		}
+
+		if err := database.Seed(getDataPath(cfg), seedOptions); err != nil {
+			return fmt.Errorf("seeding failed: %w", err)
+		}
+		if !seedOptions.DryRun {
+			log.Println("✅ Seeding completed successfully!")
+		}
+		return nil
+	},
+}
+
+var freshCommand = &command{
+	name:    "fresh",
+	summary: "Drop every table, then migrate and seed from scratch",
+	help:    "Deletes all data, including users. Meant for development databases.",
+	flags: func(fs *flag.FlagSet, cfg *config.Config) {
+		fs.BoolVar(&assumeYes, "yes", false, "don't ask for confirmation")
+		dataPathFlag(fs, cfg)
+		databaseFlag(fs, cfg)
+	},
+	run: func(cfg *config.Config, args []string) error {
+		if err := confirm(assumeYes, "This will drop every table in %s, including all user data", databaseName(cfg)); err != nil {
+			return err
+		}
+		if err := connect(cfg); err != nil {
+			return err
+		}
+		defer database.Close()
+
+		// Drop all tables and re-migrate
+		log.Println("🗑️ Dropping all tables...")
+		if err := database.DropAllTables(); err != nil {
+			return fmt.Errorf("failed to drop tables: %w", err)
+		}
+
+		if err := database.Migrate(); err != nil {
+			return fmt.Errorf("migration failed: %w", err)
+		}
+
+		if err := database.Seed(getDataPath(cfg), database.SeedOptions{}); err != nil {
+			return fmt.Errorf("seeding failed: %w", err)
+		}
+		log.Println("✅ Fresh migration and seeding completed!")
+		return nil
+	},
+}
+
+var userCommand = &command{
+	name:    "user",
+	summary: "Manage user accounts",
+	commands: []*command{
+		{
+			name:    "create-admin",
+			summary: "Create an admin account, or promote an existing user",
+			help: `New accounts need a password. Without -password it's asked for on
+the terminal, or read from the first line of stdin when that isn't a
+terminal. Existing users keep their password unless -password is set.`,
+			flags: func(fs *flag.FlagSet, cfg *config.Config) {
+				fs.StringVar(&adminEmail, "email", "", "email address (required)")
+				fs.StringVar(&adminName, "name", "", "display name for a new account")
+				fs.StringVar(&adminPassword, "password", "", "password, at least 8 characters")
+				databaseFlag(fs, cfg)
+			},
+			run: runCreateAdmin,
+		},
+	},
+}
+
+var codesCommand = &command{
+	name:    "codes",
+	summary: "Manage redemption codes",
+	commands: []*command{
+		{
+			name:    "expire",
+			summary: "Deactivate codes whose expiry has passed",
+			help:    "Runs the same job the server runs every CODE_EXPIRY_INTERVAL, for use from cron.",
+			flags: func(fs *flag.FlagSet, cfg *config.Config) {
+				databaseFlag(fs, cfg)
+			},
+			run: func(cfg *config.Config, args []string) error {
+				if err := connect(cfg); err != nil {
+					return err
+				}
+				defer database.Close()
+
+				expired, err := jobs.ExpireCodes(database.DB)
+				if err != nil {
+					return fmt.Errorf("failed to expire codes: %w", err)
+				}
+				log.Printf("✅ Expired %d redemption codes", expired)
+				return nil
+			},
+		},
+	},
+}
+
+var exportCommand = &command{
+	name:    "export",
+	summary: "Write the game data in the database back to the data files",
+	help: `Writes the files seed reads, so changes made through the admin API can
+be committed. Existing files in the data path are overwritten.`,
+	flags: func(fs *flag.FlagSet, cfg *config.Config) {
+		fs.BoolVar(&assumeYes, "yes", false, "don't ask before overwriting existing files")
+		dataPathFlag(fs, cfg)
+		databaseFlag(fs, cfg)
+	},
+	run: func(cfg *config.Config, args []string) error {
+		dataPath := getDataPath(cfg)
+		if entries, err := os.ReadDir(dataPath); err == nil && len(entries) > 0 {
+			if err := confirm(assumeYes, "This will overwrite the data files in %s", dataPath); err != nil {
+				return err
+			}
+		}
+		if err := connect(cfg); err != nil {
+			return err
+		}
+		defer database.Close()
+
+		if err := database.Export(dataPath); err != nil {
+			return fmt.Errorf("export failed: %w", err)
+		}
+		log.Println("✅ Export completed successfully!")
+		return nil
+	},
+}
+
+// Values of command-specific flags. Only one command runs per process.
+var (
+	assumeYes     bool
+	migrateSteps  int
+	migrationsDir string
+	seedOptions   database.SeedOptions
+	adminEmail    string
+	adminName     string
+	adminPassword string
+)
+
+func runCreateAdmin(cfg *config.Config, args []string) error {
+	email := strings.TrimSpace(adminEmail)
+	if email == "" {
+		return errors.New("-email is required")
+	}
+	if err := connect(cfg); err != nil {
+		return err
+	}
+	defer database.Close()
+
+	var user models.User
+	err := database.DB.Where("email = ?", email).First(&user).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	exists := err == nil
+
+	password := adminPassword
+	if !exists && password == "" {
+		if password, err = readPassword(); err != nil {
+			return err
+		}
+		if password == "" {
+			return errors.New("a password is required for new accounts")
+		}
+	}
+	if password != "" {
+		if len(password) < 8 {
+			return errors.New("password must be at least 8 characters")
+		}
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+		if err != nil {
+			return fmt.Errorf("failed to hash password: %w", err)
+		}
+		user.PasswordHash = string(hash)
+	}
+
+	// Existing accounts keep their verification state: running this command
+	// doesn't prove anyone owns the address
+	user.Role = models.RoleAdmin
+	if exists {
+		if err := database.DB.Save(&user).Error; err != nil {
+			return fmt.Errorf("failed to update user: %w", err)
+		}
+		log.Printf("✅ %s is now an admin", email)
+		return nil
+	}
+
+	user.Email = email
+	user.Name = adminName
+	user.EmailVerified = true
+	if err := database.DB.Create(&user).Error; err != nil {
+		return fmt.Errorf("failed to create user: %w", err)
+	}
+	log.Printf("✅ Created admin %s", email)
+	return nil
+}
+
+// readPassword asks for a password on the terminal without echoing it, or
+// reads the first line of stdin when it isn't a terminal
+func readPassword() (string, error) {
+	fd := int(os.Stdin.Fd())
+	if term.IsTerminal(fd) {
+		fmt.Fprint(os.Stderr, "Password: ")
+		password, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", fmt.Errorf("failed to read password: %w", err)
+		}
+		return string(password), nil
+	}
+
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("failed to read password: %w", err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
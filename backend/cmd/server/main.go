@@ -1,131 +1,151 @@
 package main
 
 import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
 	"log"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 
-	"github.com/gin-gonic/gin"
 	"github.com/hsr-tools/backend/internal/config"
 	"github.com/hsr-tools/backend/internal/database"
-	"github.com/hsr-tools/backend/internal/handlers"
-	"github.com/hsr-tools/backend/internal/middleware"
 )
 
+// command is a CLI command. Commands either run something or group
+// subcommands, like "migrate up" and "user create-admin".
+type command struct {
+	name    string
+	args    string // Positional arguments, for the usage line
+	summary string
+	help    string
+
+	// flags registers the command's flags. Flags bound to cfg fields
+	// override the values config.Load read from the environment.
+	flags func(fs *flag.FlagSet, cfg *config.Config)
+	run   func(cfg *config.Config, args []string) error
+
+	commands []*command
+	fallback *command // Subcommand to run when none is given
+}
+
+const binaryName = "server"
+
+var rootCommand = &command{
+	summary: "HSR Tools API server and maintenance commands",
+	commands: []*command{
+		serveCommand,
+		migrateCommand,
+		seedCommand,
+		freshCommand,
+		userCommand,
+		codesCommand,
+		exportCommand,
+	},
+	fallback: serveCommand,
+}
+
 func main() {
-	// Load configuration
+	// Load configuration. Nothing connects to the database until a command
+	// that needs it runs.
 	cfg := config.Load()
 
-	// Connect to database
-	if err := database.Connect(cfg); err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
-	}
-	defer database.Close()
-
-	// Check for command line arguments
-	if len(os.Args) > 1 {
-		switch os.Args[1] {
-		case "migrate":
-			if err := database.Migrate(); err != nil {
-				log.Fatalf("Migration failed: %v", err)
-			}
-			log.Println("✅ Migration completed successfully!")
+	if err := rootCommand.execute(cfg, nil, os.Args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
 			return
+		}
+		log.Fatalf("Error: %v", err)
+	}
+}
 
-		case "seed":
-			// First run migrations
-			if err := database.Migrate(); err != nil {
-				log.Fatalf("Migration failed: %v", err)
-			}
-
-			// Get data path (relative to frontend)
-			dataPath := getDataPath()
-			if err := database.Seed(dataPath); err != nil {
-				log.Fatalf("Seeding failed: %v", err)
+// execute finds the subcommand named by args, parses its flags and runs it
+func (c *command) execute(cfg *config.Config, path []string, args []string) error {
+	if len(c.commands) > 0 {
+		if len(args) > 0 && (args[0] == "help" || args[0] == "-h" || args[0] == "--help") {
+			c.printUsage(path, nil)
+			return flag.ErrHelp
+		}
+		if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+			for _, sub := range c.commands {
+				if sub.name == args[0] {
+					return sub.execute(cfg, append(path, sub.name), args[1:])
+				}
 			}
-			log.Println("✅ Seeding completed successfully!")
-			return
+			c.printUsage(path, nil)
+			return fmt.Errorf("unknown command %q", strings.Join(append(path, args[0]), " "))
+		}
+		if c.fallback == nil {
+			c.printUsage(path, nil)
+			return fmt.Errorf("%q needs a command", strings.Join(path, " "))
+		}
+		return c.fallback.execute(cfg, append(path, c.fallback.name), args)
+	}
 
-		case "fresh":
-			// Drop all tables and re-migrate
-			log.Println("🗑️ Dropping all tables...")
-			if err := dropAllTables(); err != nil {
-				log.Fatalf("Failed to drop tables: %v", err)
-			}
+	fs := flag.NewFlagSet(strings.Join(path, " "), flag.ContinueOnError)
+	if c.flags != nil {
+		c.flags(fs, cfg)
+	}
+	fs.Usage = func() { c.printUsage(path, fs) }
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	return c.run(cfg, fs.Args())
+}
 
-			if err := database.Migrate(); err != nil {
-				log.Fatalf("Migration failed: %v", err)
-			}
+// printUsage prints a group's subcommands, or a command's help and flags
+func (c *command) printUsage(path []string, fs *flag.FlagSet) {
+	out := flag.CommandLine.Output()
+	name := strings.TrimSpace(binaryName + " " + strings.Join(path, " "))
 
-			dataPath := getDataPath()
-			if err := database.Seed(dataPath); err != nil {
-				log.Fatalf("Seeding failed: %v", err)
-			}
-			log.Println("✅ Fresh migration and seeding completed!")
-			return
+	if len(c.commands) > 0 {
+		fmt.Fprintf(out, "%s\n\nUsage: %s <command> [flags]\n\nCommands:\n", c.summary, name)
+		for _, sub := range c.commands {
+			fmt.Fprintf(out, "  %-14s %s\n", sub.name, sub.summary)
 		}
+		fmt.Fprintf(out, "\nRun \"%s <command> -h\" for a command's flags.\n", name)
+		return
 	}
 
-	// Run migrations on startup
-	if err := database.Migrate(); err != nil {
-		log.Fatalf("Migration failed: %v", err)
+	fmt.Fprintf(out, "Usage: %s\n\n%s\n", strings.TrimSpace(name+" [flags] "+c.args), c.summary)
+	if c.help != "" {
+		fmt.Fprintf(out, "\n%s\n", c.help)
 	}
-
-	// Setup Gin router
-	if cfg.Environment == "production" {
-		gin.SetMode(gin.ReleaseMode)
+	hasFlags := false
+	fs.VisitAll(func(*flag.Flag) { hasFlags = true })
+	if hasFlags {
+		fmt.Fprintln(out, "\nFlags:")
+		fs.PrintDefaults()
 	}
+}
 
-	r := gin.Default()
-
-	// Apply middleware
-	r.Use(middleware.CORS())
-
-	// Setup routes
-	api := r.Group("/api")
-	{
-		// Health check
-		api.GET("/health", func(c *gin.Context) {
-			c.JSON(200, gin.H{"status": "ok", "message": "HSR Tools API is running"})
-		})
-
-		// Auth routes
-		auth := api.Group("/auth")
-		{
-			auth.POST("/register", handlers.Register)
-			auth.POST("/login", handlers.Login)
-			auth.POST("/refresh", handlers.RefreshToken)
-		}
+// databaseFlag lets a command point at another database than DATABASE_URL.
+// It has no default in the help output, which would print the credentials.
+func databaseFlag(fs *flag.FlagSet, cfg *config.Config) {
+	fs.Func("database-url", "PostgreSQL connection URL (DATABASE_URL)", func(value string) error {
+		cfg.DatabaseURL = value
+		return nil
+	})
+}
 
-		// User routes (protected)
-		users := api.Group("/users")
-		users.Use(middleware.Auth())
-		{
-			users.GET("/me", handlers.GetCurrentUser)
-			users.PATCH("/uid", handlers.SetUID)
-			users.GET("/characters", handlers.GetUserCharacters)
-			users.POST("/characters", handlers.AddUserCharacter)
-			users.PATCH("/characters/:id", handlers.UpdateUserCharacter)
-			users.DELETE("/characters/:id", handlers.DeleteUserCharacter)
-		}
+func dataPathFlag(fs *flag.FlagSet, cfg *config.Config) {
+	fs.StringVar(&cfg.DataPath, "data-path", cfg.DataPath, "directory with the game data files (DATA_PATH, found automatically when empty)")
+}
 
-		// Public game data routes
-		api.GET("/characters", handlers.GetCharacters)
-		api.GET("/characters/:id", handlers.GetCharacterByID)
-		api.GET("/banners", handlers.GetBanners)
-		api.GET("/codes", handlers.GetCodes)
-		api.GET("/events", handlers.GetEvents)
-		api.GET("/mihomo/:uid", handlers.GetMihomoProfile)
+func connect(cfg *config.Config) error {
+	if err := database.Connect(cfg); err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
 	}
+	return nil
+}
 
-	// Start server
-	log.Printf("🚀 Server starting on port %s", cfg.Port)
-	if err := r.Run(":" + cfg.Port); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+func getDataPath(cfg *config.Config) string {
+	if cfg.DataPath != "" {
+		return cfg.DataPath
 	}
-}
 
-func getDataPath() string {
 	// Try to find data path relative to current directory
 	candidates := []string{
 		"../src/data", // When running from backend/
@@ -144,28 +164,36 @@ func getDataPath() string {
 	return "../src/data"
 }
 
-func dropAllTables() error {
-	tables := []string{
-		"user_characters",
-		"banner_characters",
-		"character_build_substats",
-		"character_build_sets",
-		"character_builds",
-		"character_skills",
-		"characters",
-		"relic_sets",
-		"paths",
-		"elements",
-		"banners",
-		"codes",
-		"events",
-		"users",
+// databaseName describes the target database for confirmation prompts,
+// without the credentials
+func databaseName(cfg *config.Config) string {
+	u, err := url.Parse(cfg.DatabaseURL)
+	if err != nil || u.Host == "" {
+		return "the configured database"
 	}
+	return fmt.Sprintf("%q on %s", strings.TrimPrefix(u.Path, "/"), u.Host)
+}
 
-	for _, table := range tables {
-		if err := database.DB.Exec("DROP TABLE IF EXISTS " + table + " CASCADE").Error; err != nil {
-			log.Printf("Warning: Failed to drop table %s: %v", table, err)
-		}
+// errNotConfirmed is returned when a destructive command is declined
+var errNotConfirmed = errors.New("aborted, nothing was changed")
+
+// confirm asks before a destructive action. It passes when yes is set and
+// fails when there's no terminal to ask on, so scripts must pass -yes.
+func confirm(yes bool, format string, args ...interface{}) error {
+	if yes {
+		return nil
 	}
-	return nil
+	message := fmt.Sprintf(format, args...)
+
+	if info, err := os.Stdin.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return fmt.Errorf("%s; pass -yes to confirm", message)
+	}
+
+	fmt.Fprintf(os.Stderr, "⚠️  %s\nContinue? [y/N] ", message)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return nil
+	}
+	return errNotConfirmed
 }
@@ -0,0 +1,274 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hsr-tools/backend/internal/config"
+	"github.com/hsr-tools/backend/internal/database"
+	"github.com/hsr-tools/backend/internal/engine"
+	"github.com/hsr-tools/backend/internal/handlers"
+	"github.com/hsr-tools/backend/internal/jobs"
+	"github.com/hsr-tools/backend/internal/mailer"
+	"github.com/hsr-tools/backend/internal/middleware"
+	"github.com/hsr-tools/backend/internal/mihomo"
+	"github.com/hsr-tools/backend/internal/oauth"
+)
+
+var serveCommand = &command{
+	name:    "serve",
+	summary: "Run the HTTP API server (the default command)",
+	help: `Starts the API server. Pending migrations are applied first unless
+-migrate=false is given, in which case they must be applied with
+"migrate up" before the new code is deployed.
+
+On SIGTERM or SIGINT the server fails /readyz, waits SHUTDOWN_DELAY, then
+stops accepting connections and lets in-flight requests finish.`,
+	flags: func(fs *flag.FlagSet, cfg *config.Config) {
+		fs.StringVar(&cfg.Port, "port", cfg.Port, "port to listen on (PORT)")
+		fs.StringVar(&cfg.Environment, "env", cfg.Environment, "environment, \"production\" enables release mode (GO_ENV)")
+		fs.BoolVar(&cfg.AutoMigrate, "migrate", cfg.AutoMigrate, "apply pending migrations on startup (AUTO_MIGRATE)")
+		fs.DurationVar(&cfg.ShutdownTimeout, "shutdown-timeout", cfg.ShutdownTimeout, "time in-flight requests get to finish on SIGTERM (SHUTDOWN_TIMEOUT)")
+		fs.DurationVar(&cfg.CodeExpiryInterval, "code-expiry-interval", cfg.CodeExpiryInterval, "how often expired codes are deactivated, 0 to disable (CODE_EXPIRY_INTERVAL)")
+		dataPathFlag(fs, cfg)
+		databaseFlag(fs, cfg)
+	},
+	run: runServe,
+}
+
+func runServe(cfg *config.Config, args []string) error {
+	if err := connect(cfg); err != nil {
+		return err
+	}
+	defer database.Close()
+
+	if cfg.AutoMigrate {
+		if err := database.Migrate(); err != nil {
+			return fmt.Errorf("migration failed: %w", err)
+		}
+	}
+
+	dataPath := getDataPath(cfg)
+
+	// Load gacha rates used by the pull simulator
+	if err := engine.LoadPityRates(dataPath); err != nil {
+		log.Printf("Warning: using built-in pity rates: %v", err)
+	}
+
+	// Load substat roll values used by the relic scorer
+	if err := engine.LoadSubstatValues(dataPath); err != nil {
+		log.Printf("Warning: using built-in substat values: %v", err)
+	}
+
+	// Profile proxy with in-memory and database caching
+	mihomo.Setup(cfg, database.DB)
+
+	// Outgoing mail for verification and password resets
+	mailer.Setup(cfg)
+
+	// OAuth login providers
+	oauth.Setup(cfg)
+
+	// Stop on SIGINT or SIGTERM. Background jobs share the context, so they
+	// stop with the server.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Deactivate redemption codes once they expire
+	expiryDone := jobs.StartCodeExpiry(ctx, database.DB, cfg.CodeExpiryInterval)
+
+	srv := &http.Server{
+		Addr:              ":" + cfg.Port,
+		Handler:           setupRouter(cfg),
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		ReadTimeout:       cfg.ReadTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+	}
+
+	// Start server
+	serveErr := make(chan error, 1)
+	go func() {
+		log.Printf("🚀 Server starting on port %s", cfg.Port)
+		serveErr <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		return fmt.Errorf("failed to start server: %w", err)
+	case <-ctx.Done():
+	}
+	stop() // A second signal kills the process straight away
+
+	log.Println("🛑 Shutting down, draining in-flight requests...")
+	handlers.MarkShuttingDown()
+	time.Sleep(cfg.ShutdownDelay)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("graceful shutdown failed: %w", err)
+	}
+	<-expiryDone
+	if err := mailer.Stop(shutdownCtx); err != nil {
+		log.Printf("⚠ %v", err)
+	}
+
+	log.Println("✅ Server stopped")
+	return nil
+}
+
+// setupRouter registers the middleware and every API route
+func setupRouter(cfg *config.Config) *gin.Engine {
+	// Setup Gin router
+	if cfg.Environment == "production" {
+		gin.SetMode(gin.ReleaseMode)
+	}
+
+	r := gin.Default()
+
+	// Apply middleware
+	r.Use(middleware.CORS())
+
+	// Probes for the container orchestrator
+	r.GET("/healthz", handlers.Healthz)
+	r.GET("/readyz", handlers.Readyz)
+
+	// Setup routes
+	api := r.Group("/api")
+	{
+		// Health check, kept for existing clients. Probes should use /healthz and /readyz.
+		api.GET("/health", func(c *gin.Context) {
+			c.JSON(200, gin.H{"status": "ok", "message": "HSR Tools API is running"})
+		})
+
+		// Auth routes
+		auth := api.Group("/auth")
+		{
+			auth.POST("/register", handlers.Register)
+			auth.POST("/login", handlers.Login)
+			auth.POST("/refresh", handlers.RefreshToken)
+			auth.POST("/logout", handlers.Logout)
+			auth.POST("/logout-all", middleware.Auth(), handlers.LogoutAll)
+
+			auth.GET("/oauth/providers", handlers.GetOAuthProviders)
+			auth.GET("/oauth/:provider/start", handlers.StartOAuthLogin)
+			auth.GET("/oauth/:provider/callback", handlers.OAuthCallback)
+			auth.POST("/verify", handlers.VerifyEmail)
+			auth.POST("/resend-verification", handlers.ResendVerification)
+			auth.POST("/forgot-password", handlers.ForgotPassword)
+			auth.POST("/reset-password", handlers.ResetPassword)
+		}
+
+		// User routes (protected)
+		users := api.Group("/users")
+		users.Use(middleware.Auth())
+		{
+			users.GET("/me", handlers.GetCurrentUser)
+			users.PATCH("/uid", handlers.SetUID)
+			users.GET("/characters", handlers.GetUserCharacters)
+			users.POST("/characters", handlers.AddUserCharacter)
+			users.POST("/characters/import", handlers.ImportUserCharacters)
+			users.PATCH("/characters/:id", handlers.UpdateUserCharacter)
+			users.GET("/characters/:id/eidolons", handlers.GetUserCharacterEidolons)
+			users.DELETE("/characters/:id", handlers.DeleteUserCharacter)
+
+			users.GET("/pull-plans", handlers.GetPullPlans)
+			users.GET("/pull-plans/:id", handlers.GetPullPlan)
+			users.POST("/pull-plans", handlers.CreatePullPlan)
+			users.PATCH("/pull-plans/:id", handlers.UpdatePullPlan)
+			users.DELETE("/pull-plans/:id", handlers.DeletePullPlan)
+
+			users.GET("/team-presets", handlers.GetTeamPresets)
+			users.GET("/team-presets/:id", handlers.GetTeamPreset)
+			users.POST("/team-presets", handlers.CreateTeamPreset)
+			users.PUT("/team-presets/:id", handlers.UpdateTeamPreset)
+			users.DELETE("/team-presets/:id", handlers.DeleteTeamPreset)
+
+			users.GET("/battles", handlers.GetUserBattles)
+			users.POST("/battles", handlers.RecordBattle)
+
+			users.GET("/activity", handlers.GetUserActivity)
+
+			users.GET("/codes", handlers.GetUserCodes)
+			users.GET("/codes/redeemed", handlers.GetRedeemedCodes)
+			users.POST("/codes/:code/redeem", handlers.RedeemCode)
+			users.DELETE("/codes/:code/redeem", handlers.UnredeemCode)
+
+			users.GET("/accounts", handlers.GetUserAccounts)
+			users.POST("/accounts/:provider/link", handlers.LinkOAuthAccount)
+			users.DELETE("/accounts/:provider", handlers.UnlinkOAuthAccount)
+		}
+
+		// Admin routes for editing game data
+		admin := api.Group("/admin")
+		admin.Use(middleware.Auth(), middleware.AdminOnly())
+		{
+			admin.POST("/banners", handlers.AdminCreateBanner)
+			admin.PUT("/banners/:id", handlers.AdminUpdateBanner)
+			admin.DELETE("/banners/:id", handlers.AdminDeleteBanner)
+
+			admin.POST("/banner-characters", handlers.AdminCreateBannerCharacter)
+			admin.PUT("/banner-characters/:id", handlers.AdminUpdateBannerCharacter)
+			admin.DELETE("/banner-characters/:id", handlers.AdminDeleteBannerCharacter)
+
+			admin.POST("/codes", handlers.AdminCreateCode)
+			admin.PUT("/codes/:id", handlers.AdminUpdateCode)
+			admin.DELETE("/codes/:id", handlers.AdminDeleteCode)
+
+			admin.POST("/events", handlers.AdminCreateEvent)
+			admin.PUT("/events/:id", handlers.AdminUpdateEvent)
+			admin.DELETE("/events/:id", handlers.AdminDeleteEvent)
+
+			admin.POST("/characters", handlers.AdminCreateCharacter)
+			admin.PUT("/characters/:id", handlers.AdminUpdateCharacter)
+			admin.DELETE("/characters/:id", handlers.AdminDeleteCharacter)
+
+			admin.POST("/character-skills", handlers.AdminCreateCharacterSkill)
+			admin.PUT("/character-skills/:id", handlers.AdminUpdateCharacterSkill)
+			admin.DELETE("/character-skills/:id", handlers.AdminDeleteCharacterSkill)
+
+			admin.POST("/character-builds", handlers.AdminCreateCharacterBuild)
+			admin.PUT("/character-builds/:id", handlers.AdminUpdateCharacterBuild)
+			admin.DELETE("/character-builds/:id", handlers.AdminDeleteCharacterBuild)
+
+			admin.PUT("/users/:id/role", handlers.AdminSetUserRole)
+			admin.GET("/audit-logs", handlers.GetAuditLogs)
+		}
+
+		// Public game data routes
+		api.GET("/characters", handlers.GetCharacters)
+		api.GET("/characters/:id", handlers.GetCharacterByID)
+		api.GET("/light-cones", handlers.GetLightCones)
+		api.GET("/light-cones/:id", handlers.GetLightConeByID)
+		api.GET("/banners", handlers.GetBanners)
+		api.GET("/codes", handlers.GetCodes)
+		api.GET("/events", handlers.GetEvents)
+		api.GET("/enemies", handlers.GetEnemies)
+		api.GET("/enemies/:id", handlers.GetEnemyByID)
+		api.GET("/lore/graph", handlers.GetLoreGraph)
+		api.GET("/lore/characters/:id/neighbors", handlers.GetLoreNeighbors)
+		api.GET("/mihomo/:uid", middleware.OptionalAuth(), handlers.GetMihomoProfile)
+		api.GET("/profiles/:uid", handlers.GetShowcaseProfile)
+		api.GET("/profiles/:uid/relic-scores", handlers.GetProfileRelicScores)
+
+		// Calculators
+		api.POST("/calc/damage", handlers.CalculateDamage)
+		api.POST("/battle/simulate", handlers.SimulateBattle)
+		api.POST("/pulls/simulate", handlers.SimulatePulls)
+		api.POST("/relics/score", handlers.ScoreRelic)
+
+		// Leaderboards
+		api.GET("/leaderboards/battles", handlers.GetBattleLeaderboard)
+	}
+
+	return r
+}
@@ -0,0 +1,436 @@
+package database
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/hsr-tools/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// Export writes the game data in the database to dataPath in the same
+// format Seed reads, so edits made through the admin API can be committed
+// back to the data files. Banners and events created without a slug get
+// one from their ID.
+func Export(dataPath string) error {
+	log.Printf("📦 Exporting game data to %s...", dataPath)
+
+	var elements []models.Element
+	if err := DB.Find(&elements).Error; err != nil {
+		return err
+	}
+	elementNames := make(map[int]string, len(elements))
+	for _, e := range elements {
+		elementNames[e.ID] = e.Name
+	}
+
+	var paths []models.Path
+	if err := DB.Find(&paths).Error; err != nil {
+		return err
+	}
+	pathNames := make(map[int]string, len(paths))
+	for _, p := range paths {
+		pathNames[p.ID] = p.Name
+	}
+
+	write := func(name string, v interface{}, count int) error {
+		file := filepath.Join(dataPath, name)
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			return err
+		}
+		var body bytes.Buffer
+		enc := json.NewEncoder(&body)
+		enc.SetEscapeHTML(false) // Keep names like "Dahlia & Firefly" readable
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+		if err := os.WriteFile(file, body.Bytes(), 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+		log.Printf("   ✓ %s: %d entries", name, count)
+		return nil
+	}
+
+	// Characters
+	var characters []models.Character
+	if err := DB.Order("release_order, id").Find(&characters).Error; err != nil {
+		return err
+	}
+	charJSON := make([]CharacterJSON, len(characters))
+	charNames := make(map[string]string, len(characters))
+	for i, c := range characters {
+		charJSON[i] = CharacterJSON{
+			ID:           c.ID,
+			CharID:       c.CharID,
+			Name:         c.Name,
+			Path:         pathNames[c.PathID],
+			Element:      elementNames[c.ElementID],
+			Rarity:       c.Rarity,
+			BaseSpeed:    c.BaseSpeed,
+			ReleaseOrder: c.ReleaseOrder,
+		}
+		charNames[c.ID] = c.Name
+	}
+	if err := write("characters.json", charJSON, len(charJSON)); err != nil {
+		return err
+	}
+
+	// Light cones
+	var lightCones []models.LightCone
+	if err := DB.Preload("Superimpositions", func(db *gorm.DB) *gorm.DB {
+		return db.Order("rank")
+	}).Order("id").Find(&lightCones).Error; err != nil {
+		return err
+	}
+	lcJSON := make([]LightConeJSON, len(lightCones))
+	for i, lc := range lightCones {
+		lcJSON[i] = LightConeJSON{
+			ID:     lc.ID,
+			GameID: lc.GameID,
+			Name:   lc.Name,
+			Rarity: lc.Rarity,
+			Path:   pathNames[lc.PathID],
+			MaxHP:  lc.MaxHP,
+			MaxAtk: lc.MaxAtk,
+			MaxDef: lc.MaxDef,
+
+			Superimpositions: []SuperimpositionJSON{},
+		}
+		for _, si := range lc.Superimpositions {
+			lcJSON[i].Superimpositions = append(lcJSON[i].Superimpositions, SuperimpositionJSON{
+				Rank:       si.Rank,
+				Desc:       si.Description,
+				Properties: si.Properties,
+			})
+		}
+	}
+	if err := write("light-cones.json", lcJSON, len(lcJSON)); err != nil {
+		return err
+	}
+
+	// Skills, keyed by character
+	var skills []models.CharacterSkill
+	if err := DB.Find(&skills).Error; err != nil {
+		return err
+	}
+	skillJSON := make(map[string]SkillJSON, len(skills))
+	for _, sk := range skills {
+		skillJSON[sk.CharacterID] = SkillJSON{
+			BasicMultiplier: sk.BasicMultiplier,
+			SkillMultiplier: sk.SkillMultiplier,
+			UltMultiplier:   sk.UltMultiplier,
+			BasicEnergy:     sk.BasicEnergy,
+			SkillEnergy:     sk.SkillEnergy,
+			UltCost:         sk.UltCost,
+			UltType:         sk.UltType,
+			Passive:         sk.Passive,
+			BaseAtk:         sk.BaseAtk,
+			BaseCritRate:    sk.BaseCritRate,
+			BaseCritDmg:     sk.BaseCritDmg,
+		}
+	}
+	if err := write("skills.json", skillJSON, len(skillJSON)); err != nil {
+		return err
+	}
+
+	// Eidolons, keyed by character
+	var eidolons []models.Eidolon
+	if err := DB.Order("character_id, rank").Find(&eidolons).Error; err != nil {
+		return err
+	}
+	eidolonJSON := make(map[string][]EidolonJSON)
+	for _, e := range eidolons {
+		eidolonJSON[e.CharacterID] = append(eidolonJSON[e.CharacterID], EidolonJSON{
+			Rank: e.Rank,
+			Name: e.Name,
+			Desc: e.Description,
+		})
+	}
+	if err := write("eidolons.json", eidolonJSON, len(eidolonJSON)); err != nil {
+		return err
+	}
+
+	// Builds, keyed by character
+	var builds []models.CharacterBuild
+	if err := DB.Preload("Substats").Preload("Sets", func(db *gorm.DB) *gorm.DB {
+		return db.Order("priority")
+	}).Preload("Sets.RelicSet").Find(&builds).Error; err != nil {
+		return err
+	}
+	buildJSON := make(map[string]BuildJSON, len(builds))
+	for _, b := range builds {
+		build := BuildJSON{
+			Name:     charNames[b.CharacterID],
+			Substats: make(map[string]float64, len(b.Substats)),
+			Sets:     []string{},
+		}
+		build.MainStats.Body = b.BodyMain
+		build.MainStats.Feet = b.FeetMain
+		build.MainStats.Orb = b.OrbMain
+		build.MainStats.Rope = b.RopeMain
+		for _, sub := range b.Substats {
+			build.Substats[sub.StatName] = sub.Weight
+		}
+		for _, set := range b.Sets {
+			build.Sets = append(build.Sets, set.RelicSet.Name)
+		}
+		buildJSON[b.CharacterID] = build
+	}
+	if err := write("optimal-builds.json", buildJSON, len(buildJSON)); err != nil {
+		return err
+	}
+
+	// Enemies
+	var enemies []models.Enemy
+	if err := DB.Preload("Weaknesses", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id")
+	}).Preload("Resistances").Preload("Variants", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id")
+	}).Order("id").Find(&enemies).Error; err != nil {
+		return err
+	}
+	enemyJSON := make([]EnemyJSON, len(enemies))
+	for i, e := range enemies {
+		enemy := EnemyJSON{
+			ID:         e.ID,
+			Name:       e.Name,
+			Type:       e.Type,
+			HP:         e.HP,
+			Speed:      e.Speed,
+			Def:        e.Def,
+			Toughness:  e.Toughness,
+			Weakness:   []string{},
+			Resistance: make(map[string]float64, len(e.Resistances)),
+			ImageURL:   e.ImageURL,
+			Variants:   []EnemyVariantJSON{},
+		}
+		for _, w := range e.Weaknesses {
+			enemy.Weakness = append(enemy.Weakness, elementNames[w.ElementID])
+		}
+		for _, r := range e.Resistances {
+			enemy.Resistance[elementNames[r.ElementID]] = r.Value
+		}
+		for _, v := range e.Variants {
+			enemy.Variants = append(enemy.Variants, EnemyVariantJSON{
+				Name:      v.Name,
+				Level:     v.Level,
+				HP:        v.HP,
+				Speed:     v.Speed,
+				Def:       v.Def,
+				Toughness: v.Toughness,
+			})
+		}
+		enemyJSON[i] = enemy
+	}
+	if err := write("enemies.json", enemyJSON, len(enemyJSON)); err != nil {
+		return err
+	}
+
+	// Banners list characters by display name
+	var banners []models.Banner
+	if err := DB.Preload("Characters", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id")
+	}).Order("start_date, id").Find(&banners).Error; err != nil {
+		return err
+	}
+	bannerJSON := make([]BannerJSON, len(banners))
+	for i, b := range banners {
+		banner := BannerJSON{
+			ID:          fmt.Sprintf("banner-%d", b.ID),
+			Phase:       b.Phase,
+			Name:        b.Name,
+			Characters:  []string{},
+			FourStars:   []string{},
+			StartDate:   b.StartDate.UTC().Format(time.RFC3339),
+			EndDate:     b.EndDate.UTC().Format(time.RFC3339),
+			Type:        b.Type,
+			BannerImage: b.ImageURL,
+		}
+		if b.Slug != nil {
+			banner.ID = *b.Slug
+		}
+		for _, link := range b.Characters {
+			if link.IsFeatured {
+				banner.Characters = append(banner.Characters, charNames[link.CharacterID])
+			} else {
+				banner.FourStars = append(banner.FourStars, charNames[link.CharacterID])
+			}
+		}
+		bannerJSON[i] = banner
+	}
+	if err := write("banners.json", bannerJSON, len(bannerJSON)); err != nil {
+		return err
+	}
+
+	// Codes. The database doesn't tell new codes from active ones, so every
+	// active code is exported as "active".
+	var codes []models.Code
+	if err := DB.Order("created_at DESC, id").Find(&codes).Error; err != nil {
+		return err
+	}
+	codeJSON := make([]CodeJSON, len(codes))
+	for i, c := range codes {
+		code := CodeJSON{
+			Code:    c.Code,
+			Rewards: c.Rewards,
+			Source:  c.Source,
+			AddedAt: c.CreatedAt.UTC().Format(time.RFC3339),
+			Status:  "active",
+		}
+		if !c.IsActive {
+			code.Status = "expired"
+		}
+		if c.ExpiresAt != nil {
+			expires := c.ExpiresAt.UTC().Format(time.RFC3339)
+			code.ExpiresAt = &expires
+		}
+		codeJSON[i] = code
+	}
+	if err := write("codes.json", codeJSON, len(codeJSON)); err != nil {
+		return err
+	}
+
+	// Events
+	var events []models.Event
+	if err := DB.Order("start_date, id").Find(&events).Error; err != nil {
+		return err
+	}
+	eventJSON := make([]EventJSON, len(events))
+	for i, e := range events {
+		event := EventJSON{
+			ID:          fmt.Sprintf("event-%d", e.ID),
+			Name:        e.Name,
+			Type:        e.Type,
+			StartDate:   e.StartDate.UTC().Format(time.RFC3339),
+			EndDate:     e.EndDate.UTC().Format(time.RFC3339),
+			Rewards:     e.Rewards,
+			Description: e.Description,
+			ImageURL:    e.ImageURL,
+		}
+		if e.Slug != nil {
+			event.ID = *e.Slug
+		}
+		if event.Rewards == nil {
+			event.Rewards = []string{}
+		}
+		eventJSON[i] = event
+	}
+	if err := write("events.json", eventJSON, len(eventJSON)); err != nil {
+		return err
+	}
+
+	return exportLore(write)
+}
+
+// exportLore writes the lore graph files, which are keyed by ID except for
+// the timeline, which is in story order
+func exportLore(write func(name string, v interface{}, count int) error) error {
+	var factions []models.Faction
+	if err := DB.Find(&factions).Error; err != nil {
+		return err
+	}
+	factionJSON := make(map[string]FactionJSON, len(factions))
+	for _, f := range factions {
+		factionJSON[f.ID] = FactionJSON{
+			ID:          f.ID,
+			Name:        f.Name,
+			Type:        f.Type,
+			Description: f.Description,
+			Leader:      f.Leader,
+			Color:       f.Color,
+			Icon:        f.Icon,
+		}
+	}
+	if err := write("lore/factions.json", factionJSON, len(factionJSON)); err != nil {
+		return err
+	}
+
+	var locations []models.Location
+	if err := DB.Preload("Connections", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id")
+	}).Find(&locations).Error; err != nil {
+		return err
+	}
+	locationJSON := make(map[string]LocationJSON, len(locations))
+	for _, l := range locations {
+		location := LocationJSON{
+			ID:          l.ID,
+			Name:        l.Name,
+			Type:        l.Type,
+			Description: l.Description,
+			Areas:       l.Areas,
+			ConnectedTo: []string{},
+		}
+		for _, conn := range l.Connections {
+			location.ConnectedTo = append(location.ConnectedTo, conn.ToID)
+		}
+		locationJSON[l.ID] = location
+	}
+	if err := write("lore/locations.json", locationJSON, len(locationJSON)); err != nil {
+		return err
+	}
+
+	var lore []models.CharacterLore
+	if err := DB.Preload("OutgoingRelations", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id")
+	}).Find(&lore).Error; err != nil {
+		return err
+	}
+	loreJSON := make(map[string]CharacterLoreJSON, len(lore))
+	for _, c := range lore {
+		character := CharacterLoreJSON{
+			ID:            c.ID,
+			Name:          c.Name,
+			Title:         c.Title,
+			Element:       c.Element,
+			Path:          c.Path,
+			Bio:           c.Bio,
+			Relationships: []RelationshipJSON{},
+		}
+		if c.FactionID != nil {
+			character.Faction = *c.FactionID
+		}
+		for _, r := range c.OutgoingRelations {
+			character.Relationships = append(character.Relationships, RelationshipJSON{
+				Target: r.ToID,
+				Type:   r.Type,
+				Label:  r.Label,
+			})
+		}
+		loreJSON[c.ID] = character
+	}
+	if err := write("lore/characters-lore.json", loreJSON, len(loreJSON)); err != nil {
+		return err
+	}
+
+	var timeline []models.TimelineEvent
+	if err := DB.Preload("Characters", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id")
+	}).Order("sort_order, id").Find(&timeline).Error; err != nil {
+		return err
+	}
+	timelineJSON := make([]TimelineEventJSON, len(timeline))
+	for i, e := range timeline {
+		event := TimelineEventJSON{
+			ID:          e.ID,
+			Title:       e.Title,
+			Chapter:     e.Chapter,
+			Description: e.Description,
+			Characters:  []string{},
+		}
+		if e.LocationID != nil {
+			event.Location = *e.LocationID
+		}
+		for _, link := range e.Characters {
+			event.Characters = append(event.Characters, link.CharacterID)
+		}
+		timelineJSON[i] = event
+	}
+	return write("lore/timeline.json", timelineJSON, len(timelineJSON))
+}
@@ -0,0 +1,480 @@
+package database
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hsr-tools/backend/internal/database/migrations"
+	"gorm.io/gorm"
+)
+
+// migrationLockID is the Postgres advisory lock held while migrating, so
+// two instances starting at once don't both apply the same migration
+const migrationLockID = 7234001
+
+// noTransaction marks a migration that can't run in a transaction, such as
+// one using CREATE INDEX CONCURRENTLY. It must be on the first line.
+const noTransaction = "-- migrate: no-transaction"
+
+// baselineVersion is the initial schema, which databases created by the old
+// AutoMigrate setup already have
+const baselineVersion = 20261017000000
+
+var (
+	migrationFilePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+	migrationNameChars   = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// Migration is one versioned schema change
+type Migration struct {
+	Version  int64
+	Name     string
+	Up       string
+	Down     string
+	Checksum string // SHA-256 of the up SQL
+}
+
+// SchemaMigration is a row in schema_migrations
+type SchemaMigration struct {
+	Version   int64     `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"not null;size:255"`
+	Checksum  string    `gorm:"not null;size:64"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+// MigrationStatus describes a migration file and whether it has been applied
+type MigrationStatus struct {
+	Version   int64
+	Name      string
+	AppliedAt *time.Time
+	Modified  bool // The file changed after it was applied
+	Missing   bool // Applied, but the file no longer exists
+}
+
+// State is a short description for status output
+func (s MigrationStatus) State() string {
+	switch {
+	case s.Missing:
+		return "missing"
+	case s.Modified:
+		return "modified"
+	case s.AppliedAt != nil:
+		return "applied"
+	default:
+		return "pending"
+	}
+}
+
+// embeddedMigrations loads the migrations built into the binary once, for
+// callers like the readiness probe that need them on every request. The
+// list is shared, so it must not be modified.
+var embeddedMigrations = sync.OnceValues(func() ([]Migration, error) {
+	return LoadMigrations(migrations.FS)
+})
+
+// LoadMigrations reads and pairs the migration files, sorted by version
+func LoadMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sql") {
+			continue
+		}
+		m := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if m == nil {
+			return nil, fmt.Errorf("invalid migration file name %q", entry.Name())
+		}
+		version, err := strconv.ParseInt(m[1], 10, 64)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("invalid migration version in %q", entry.Name())
+		}
+		body, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = migration
+		} else if migration.Name != m[2] {
+			return nil, fmt.Errorf("migration %d has files with different names", version)
+		}
+		if m[3] == "up" {
+			migration.Up = string(body)
+			sum := sha256.Sum256(body)
+			migration.Checksum = hex.EncodeToString(sum[:])
+		} else {
+			migration.Down = string(body)
+		}
+	}
+
+	list := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up file", m.Version, m.Name)
+		}
+		list = append(list, *m)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Version < list[j].Version })
+	return list, nil
+}
+
+// Migrate applies every pending migration
+func Migrate() error {
+	return MigrateUp(0)
+}
+
+// MigrateUp applies up to steps pending migrations, or all of them if
+// steps is 0. Each migration runs in its own transaction with its
+// schema_migrations row, so a failure leaves it unapplied.
+func MigrateUp(steps int) error {
+	log.Println("🔄 Running database migrations...")
+
+	available, err := embeddedMigrations()
+	if err != nil {
+		return err
+	}
+
+	return withMigrationLock(func(conn *gorm.DB) error {
+		applied, err := appliedMigrations(conn)
+		if err != nil {
+			return err
+		}
+		if err := checkApplied(available, applied); err != nil {
+			return err
+		}
+
+		var latest int64
+		for version := range applied {
+			latest = max(latest, version)
+		}
+
+		count := 0
+		for _, m := range available {
+			if _, ok := applied[m.Version]; ok {
+				continue
+			}
+			if steps > 0 && count == steps {
+				break
+			}
+			// Applying an older migration after newer ones could run it
+			// against a schema it wasn't written for
+			if m.Version < latest {
+				return fmt.Errorf("migration %d_%s is older than the latest applied migration %d; give it a new version", m.Version, m.Name, latest)
+			}
+
+			log.Printf("   ↑ %d_%s", m.Version, m.Name)
+			record := SchemaMigration{Version: m.Version, Name: m.Name, Checksum: m.Checksum, AppliedAt: time.Now()}
+			if err := runMigration(conn, m.Up, func(tx *gorm.DB) error {
+				return tx.Create(&record).Error
+			}); err != nil {
+				return fmt.Errorf("migration %d_%s failed: %w", m.Version, m.Name, err)
+			}
+			count++
+		}
+
+		if count == 0 {
+			log.Println("✅ Database is up to date")
+		} else {
+			log.Printf("✅ Applied %d migrations", count)
+		}
+		return nil
+	})
+}
+
+// MigrateDown rolls back the latest steps applied migrations
+func MigrateDown(steps int) error {
+	if steps < 1 {
+		return errors.New("steps must be at least 1")
+	}
+
+	available, err := embeddedMigrations()
+	if err != nil {
+		return err
+	}
+	files := make(map[int64]Migration, len(available))
+	for _, m := range available {
+		files[m.Version] = m
+	}
+
+	return withMigrationLock(func(conn *gorm.DB) error {
+		var applied []SchemaMigration
+		if err := conn.Order("version DESC").Limit(steps).Find(&applied).Error; err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			log.Println("No migrations to roll back")
+			return nil
+		}
+
+		for _, a := range applied {
+			m, ok := files[a.Version]
+			if !ok {
+				return fmt.Errorf("migration %d_%s is applied but its files are missing", a.Version, a.Name)
+			}
+			if m.Down == "" {
+				return fmt.Errorf("migration %d_%s has no down file", m.Version, m.Name)
+			}
+
+			log.Printf("   ↓ %d_%s", m.Version, m.Name)
+			if err := runMigration(conn, m.Down, func(tx *gorm.DB) error {
+				return tx.Delete(&SchemaMigration{}, a.Version).Error
+			}); err != nil {
+				return fmt.Errorf("rolling back %d_%s failed: %w", m.Version, m.Name, err)
+			}
+		}
+
+		log.Printf("✅ Rolled back %d migrations", len(applied))
+		return nil
+	})
+}
+
+// MigrationStatuses lists every migration file and applied migration
+func MigrationStatuses(ctx context.Context) ([]MigrationStatus, error) {
+	db := DB.WithContext(ctx)
+	available, err := embeddedMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	var applied map[int64]SchemaMigration
+	if db.Migrator().HasTable(&SchemaMigration{}) {
+		if applied, err = appliedMigrations(db); err != nil {
+			return nil, err
+		}
+	}
+
+	statuses := make([]MigrationStatus, 0, len(available))
+	for _, m := range available {
+		status := MigrationStatus{Version: m.Version, Name: m.Name}
+		if a, ok := applied[m.Version]; ok {
+			appliedAt := a.AppliedAt
+			status.AppliedAt = &appliedAt
+			status.Modified = a.Checksum != m.Checksum
+			delete(applied, m.Version)
+		}
+		statuses = append(statuses, status)
+	}
+	for _, a := range applied {
+		appliedAt := a.AppliedAt
+		statuses = append(statuses, MigrationStatus{Version: a.Version, Name: a.Name, AppliedAt: &appliedAt, Missing: true})
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses, nil
+}
+
+// PendingMigrations counts migrations that haven't been applied yet
+func PendingMigrations(ctx context.Context) (int, error) {
+	statuses, err := MigrationStatuses(ctx)
+	if err != nil {
+		return 0, err
+	}
+	pending := 0
+	for _, s := range statuses {
+		if s.AppliedAt == nil {
+			pending++
+		}
+	}
+	return pending, nil
+}
+
+// CreateMigration writes an empty up and down file for a new migration
+func CreateMigration(dir, name string) ([]string, error) {
+	name = strings.Trim(migrationNameChars.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if name == "" {
+		return nil, errors.New("migration name is required")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	version := time.Now().UTC().Format("20060102150405")
+	var paths []string
+	for _, direction := range []string{"up", "down"} {
+		path := filepath.Join(dir, fmt.Sprintf("%s_%s.%s.sql", version, name, direction))
+		body := fmt.Sprintf("-- %s: %s\n\n", strings.ToUpper(direction), name)
+		if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+			return paths, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// withMigrationLock runs fn on one connection while holding the migration
+// lock, creating schema_migrations first if needed
+func withMigrationLock(fn func(conn *gorm.DB) error) error {
+	return DB.Connection(func(conn *gorm.DB) error {
+		if err := conn.Exec("SELECT pg_advisory_lock(?)", migrationLockID).Error; err != nil {
+			return fmt.Errorf("failed to take migration lock: %w", err)
+		}
+		defer conn.Exec("SELECT pg_advisory_unlock(?)", migrationLockID)
+
+		if !conn.Migrator().HasTable(&SchemaMigration{}) {
+			if err := createSchemaMigrations(conn); err != nil {
+				return err
+			}
+		}
+		return fn(conn)
+	})
+}
+
+// baselineTables are the tables AutoMigrate created before versioned
+// migrations, and so the tables the baseline migration creates
+var baselineTables = []string{
+	"elements", "paths", "relic_sets",
+	"characters", "character_skills", "character_builds", "character_build_sets", "character_build_substats",
+	"users", "user_characters",
+	"banners", "banner_characters", "codes", "events",
+}
+
+// createSchemaMigrations creates the tracking table. A database that already
+// has the baseline's tables was set up by AutoMigrate, so it gets the
+// baseline marked as applied rather than having it run again.
+func createSchemaMigrations(conn *gorm.DB) error {
+	return conn.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`CREATE TABLE schema_migrations (
+			version bigint PRIMARY KEY,
+			name varchar(255) NOT NULL,
+			checksum varchar(64) NOT NULL,
+			applied_at timestamptz NOT NULL
+		)`).Error; err != nil {
+			return fmt.Errorf("failed to create schema_migrations: %w", err)
+		}
+
+		var tables []string
+		if err := tx.Raw("SELECT tablename FROM pg_tables WHERE schemaname = CURRENT_SCHEMA() AND tablename <> 'schema_migrations'").Scan(&tables).Error; err != nil {
+			return err
+		}
+		if len(tables) == 0 {
+			return nil
+		}
+		if err := checkBaselineSchema(tables); err != nil {
+			return err
+		}
+
+		available, err := embeddedMigrations()
+		if err != nil {
+			return err
+		}
+		for _, m := range available {
+			if m.Version == baselineVersion {
+				log.Printf("   ✓ Existing schema found, marking %d_%s as applied", m.Version, m.Name)
+				return tx.Create(&SchemaMigration{Version: m.Version, Name: m.Name, Checksum: m.Checksum, AppliedAt: time.Now()}).Error
+			}
+		}
+		return nil
+	})
+}
+
+// checkBaselineSchema makes sure an existing database has exactly the
+// baseline's tables before it's marked as applied. Any other set of tables
+// came from somewhere else, and the later migrations would fail halfway.
+func checkBaselineSchema(tables []string) error {
+	existing := make(map[string]bool, len(tables))
+	for _, table := range tables {
+		existing[table] = true
+	}
+
+	var missing, unexpected []string
+	for _, table := range baselineTables {
+		if !existing[table] {
+			missing = append(missing, table)
+		}
+		delete(existing, table)
+	}
+	for table := range existing {
+		unexpected = append(unexpected, table)
+	}
+	sort.Strings(unexpected)
+
+	if len(missing) > 0 || len(unexpected) > 0 {
+		return fmt.Errorf("existing schema doesn't match the baseline migration (missing: %s; unexpected: %s); migrate it by hand or start from an empty database",
+			listOrNone(missing), listOrNone(unexpected))
+	}
+	return nil
+}
+
+func listOrNone(names []string) string {
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, ", ")
+}
+
+func appliedMigrations(conn *gorm.DB) (map[int64]SchemaMigration, error) {
+	var rows []SchemaMigration
+	if err := conn.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	applied := make(map[int64]SchemaMigration, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+	return applied, nil
+}
+
+// checkApplied refuses to migrate when an applied migration's file was
+// edited, since the database no longer matches what the files describe
+func checkApplied(available []Migration, applied map[int64]SchemaMigration) error {
+	var modified []string
+	for _, m := range available {
+		if a, ok := applied[m.Version]; ok && a.Checksum != m.Checksum {
+			modified = append(modified, fmt.Sprintf("%d_%s", m.Version, m.Name))
+		}
+	}
+	if len(modified) > 0 {
+		return fmt.Errorf("applied migrations were changed since they ran: %s; add a new migration instead", strings.Join(modified, ", "))
+	}
+	return nil
+}
+
+// runMigration runs the SQL and records the result, in one transaction
+// unless the migration opts out
+func runMigration(conn *gorm.DB, sql string, record func(tx *gorm.DB) error) error {
+	if strings.HasPrefix(strings.TrimSpace(sql), noTransaction) {
+		if err := conn.Exec(sql).Error; err != nil {
+			return err
+		}
+		return record(conn)
+	}
+	return conn.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(sql).Error; err != nil {
+			return err
+		}
+		return record(tx)
+	})
+}
+
+// DropAllTables drops every table in the current schema, including
+// schema_migrations, so the next migration starts from nothing
+func DropAllTables() error {
+	var tables []string
+	if err := DB.Raw("SELECT tablename FROM pg_tables WHERE schemaname = CURRENT_SCHEMA()").Scan(&tables).Error; err != nil {
+		return err
+	}
+	for _, table := range tables {
+		if err := DB.Exec(`DROP TABLE IF EXISTS "` + table + `" CASCADE`).Error; err != nil {
+			return fmt.Errorf("failed to drop %s: %w", table, err)
+		}
+	}
+	return nil
+}
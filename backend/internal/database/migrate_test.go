@@ -0,0 +1,167 @@
+package database
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/hsr-tools/backend/internal/database/migrations"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	file := func(body string) *fstest.MapFile { return &fstest.MapFile{Data: []byte(body)} }
+
+	tests := []struct {
+		name     string
+		files    fstest.MapFS
+		versions []int64
+		wantErr  string
+	}{
+		{
+			name: "sorted by version",
+			files: fstest.MapFS{
+				"20260102000000_second.up.sql":   file("SELECT 2;"),
+				"20260102000000_second.down.sql": file("SELECT -2;"),
+				"20260101000000_first.up.sql":    file("SELECT 1;"),
+			},
+			versions: []int64{20260101000000, 20260102000000},
+		},
+		{
+			name: "other files are ignored",
+			files: fstest.MapFS{
+				"20260101000000_first.up.sql": file("SELECT 1;"),
+				"migrations.go":               file("package migrations"),
+				"README.md":                   file("notes"),
+			},
+			versions: []int64{20260101000000},
+		},
+		{
+			name:     "empty directory",
+			files:    fstest.MapFS{},
+			versions: []int64{},
+		},
+		{
+			name:    "missing version",
+			files:   fstest.MapFS{"first.up.sql": file("SELECT 1;")},
+			wantErr: "invalid migration file name",
+		},
+		{
+			name:    "missing direction",
+			files:   fstest.MapFS{"20260101000000_first.sql": file("SELECT 1;")},
+			wantErr: "invalid migration file name",
+		},
+		{
+			name:    "uppercase name",
+			files:   fstest.MapFS{"20260101000000_AddThing.up.sql": file("SELECT 1;")},
+			wantErr: "invalid migration file name",
+		},
+		{
+			name:    "dash separator",
+			files:   fstest.MapFS{"20260101000000-first.up.sql": file("SELECT 1;")},
+			wantErr: "invalid migration file name",
+		},
+		{
+			name:    "version overflows",
+			files:   fstest.MapFS{"99999999999999999999_first.up.sql": file("SELECT 1;")},
+			wantErr: "invalid migration version",
+		},
+		{
+			name:    "zero version",
+			files:   fstest.MapFS{"0_first.up.sql": file("SELECT 1;")},
+			wantErr: "invalid migration version",
+		},
+		{
+			name:    "down without up",
+			files:   fstest.MapFS{"20260101000000_first.down.sql": file("SELECT 1;")},
+			wantErr: "has no up file",
+		},
+		{
+			name: "names disagree",
+			files: fstest.MapFS{
+				"20260101000000_first.up.sql":   file("SELECT 1;"),
+				"20260101000000_other.down.sql": file("SELECT 1;"),
+			},
+			wantErr: "different names",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := LoadMigrations(tt.files)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LoadMigrations() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(list) != len(tt.versions) {
+				t.Fatalf("got %d migrations, want %d", len(list), len(tt.versions))
+			}
+			for i, m := range list {
+				if m.Version != tt.versions[i] {
+					t.Errorf("migration %d has version %d, want %d", i, m.Version, tt.versions[i])
+				}
+				if m.Checksum == "" {
+					t.Errorf("migration %d has no checksum", m.Version)
+				}
+			}
+		})
+	}
+}
+
+func TestEmbeddedMigrations(t *testing.T) {
+	list, err := LoadMigrations(migrations.FS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) == 0 || list[0].Version != baselineVersion {
+		t.Fatalf("first migration should be the baseline %d", baselineVersion)
+	}
+	for _, m := range list {
+		if strings.TrimSpace(m.Down) == "" {
+			t.Errorf("migration %d_%s has no down file", m.Version, m.Name)
+		}
+	}
+
+	// The baseline creates exactly the tables existing databases are
+	// checked against
+	for _, table := range baselineTables {
+		if !strings.Contains(list[0].Up, `CREATE TABLE "`+table+`"`) {
+			t.Errorf("baseline doesn't create %s", table)
+		}
+	}
+	if got := strings.Count(list[0].Up, "CREATE TABLE"); got != len(baselineTables) {
+		t.Errorf("baseline creates %d tables, want %d", got, len(baselineTables))
+	}
+}
+
+func TestCheckBaselineSchema(t *testing.T) {
+	tests := []struct {
+		name    string
+		tables  []string
+		wantErr string
+	}{
+		{name: "exact baseline", tables: baselineTables},
+		{name: "missing table", tables: baselineTables[1:], wantErr: "missing: elements; unexpected: none"},
+		{name: "newer tables", tables: append([]string{"eidolons", "battles"}, baselineTables...), wantErr: "missing: none; unexpected: battles, eidolons"},
+		{name: "unrelated schema", tables: []string{"posts"}, wantErr: "unexpected: posts"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkBaselineSchema(tt.tables)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("checkBaselineSchema() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,16 @@
+-- Drops every table in the baseline schema
+
+DROP TABLE "events";
+DROP TABLE "codes";
+DROP TABLE "banner_characters";
+DROP TABLE "banners";
+DROP TABLE "user_characters";
+DROP TABLE "users";
+DROP TABLE "character_build_substats";
+DROP TABLE "character_build_sets";
+DROP TABLE "character_builds";
+DROP TABLE "character_skills";
+DROP TABLE "characters";
+DROP TABLE "relic_sets";
+DROP TABLE "paths";
+DROP TABLE "elements";
@@ -0,0 +1,180 @@
+-- Baseline schema, matching what GORM AutoMigrate created before versioned
+-- migrations. Databases created that way are marked as already having it.
+
+CREATE TABLE "elements" (
+    "id" bigserial,
+    "name" varchar(50) NOT NULL,
+    "icon_url" varchar(255),
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX "idx_elements_name" ON "elements" ("name");
+
+CREATE TABLE "paths" (
+    "id" bigserial,
+    "name" varchar(50) NOT NULL,
+    "icon_url" varchar(255),
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX "idx_paths_name" ON "paths" ("name");
+
+CREATE TABLE "relic_sets" (
+    "id" bigserial,
+    "name" varchar(100) NOT NULL,
+    "type" varchar(20),
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX "idx_relic_sets_name" ON "relic_sets" ("name");
+
+CREATE TABLE "characters" (
+    "id" varchar(50),
+    "char_id" varchar(10) NOT NULL,
+    "name" varchar(100) NOT NULL,
+    "element_id" bigint NOT NULL,
+    "path_id" bigint NOT NULL,
+    "rarity" bigint NOT NULL DEFAULT 4,
+    "base_speed" bigint NOT NULL DEFAULT 100,
+    "release_order" bigint NOT NULL DEFAULT 0,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_elements_characters" FOREIGN KEY ("element_id") REFERENCES "elements"("id"),
+    CONSTRAINT "fk_paths_characters" FOREIGN KEY ("path_id") REFERENCES "paths"("id")
+);
+CREATE INDEX "idx_characters_release_order" ON "characters" ("release_order");
+CREATE INDEX "idx_characters_path_id" ON "characters" ("path_id");
+CREATE INDEX "idx_characters_element_id" ON "characters" ("element_id");
+CREATE UNIQUE INDEX "idx_characters_char_id" ON "characters" ("char_id");
+
+CREATE TABLE "character_skills" (
+    "id" bigserial,
+    "character_id" varchar(50) NOT NULL,
+    "basic_multiplier" decimal(4,2) DEFAULT 1,
+    "skill_multiplier" decimal(4,2) DEFAULT 1,
+    "ult_multiplier" decimal(4,2) DEFAULT 2,
+    "basic_energy" bigint DEFAULT 20,
+    "skill_energy" bigint DEFAULT 30,
+    "ult_cost" bigint DEFAULT 120,
+    "ult_type" varchar(20) DEFAULT 'normal',
+    "passive" text,
+    "base_atk" bigint DEFAULT 500,
+    "base_crit_rate" decimal(4,2) DEFAULT 0.05,
+    "base_crit_dmg" decimal(4,2) DEFAULT 0.5,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_characters_skills" FOREIGN KEY ("character_id") REFERENCES "characters"("id")
+);
+CREATE UNIQUE INDEX "idx_character_skills_character_id" ON "character_skills" ("character_id");
+
+CREATE TABLE "character_builds" (
+    "id" bigserial,
+    "character_id" varchar(50) NOT NULL,
+    "body_main" varchar(50),
+    "feet_main" varchar(50),
+    "orb_main" varchar(50),
+    "rope_main" varchar(50),
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_characters_build" FOREIGN KEY ("character_id") REFERENCES "characters"("id")
+);
+CREATE UNIQUE INDEX "idx_character_builds_character_id" ON "character_builds" ("character_id");
+
+CREATE TABLE "character_build_sets" (
+    "id" bigserial,
+    "build_id" bigint NOT NULL,
+    "relic_set_id" bigint NOT NULL,
+    "priority" bigint DEFAULT 1,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_character_build_sets_relic_set" FOREIGN KEY ("relic_set_id") REFERENCES "relic_sets"("id"),
+    CONSTRAINT "fk_character_builds_sets" FOREIGN KEY ("build_id") REFERENCES "character_builds"("id")
+);
+CREATE INDEX "idx_character_build_sets_relic_set_id" ON "character_build_sets" ("relic_set_id");
+CREATE INDEX "idx_character_build_sets_build_id" ON "character_build_sets" ("build_id");
+
+CREATE TABLE "character_build_substats" (
+    "id" bigserial,
+    "build_id" bigint NOT NULL,
+    "stat_name" varchar(50) NOT NULL,
+    "weight" decimal(3,2) DEFAULT 0.5,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_character_builds_substats" FOREIGN KEY ("build_id") REFERENCES "character_builds"("id")
+);
+CREATE INDEX "idx_character_build_substats_build_id" ON "character_build_substats" ("build_id");
+
+CREATE TABLE "users" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "email" text NOT NULL,
+    "password_hash" text,
+    "name" text,
+    "uid" text,
+    "nickname" text,
+    "email_verified" boolean DEFAULT false,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX "idx_users_deleted_at" ON "users" ("deleted_at");
+CREATE UNIQUE INDEX "idx_users_uid" ON "users" ("uid");
+CREATE UNIQUE INDEX "idx_users_email" ON "users" ("email");
+
+CREATE TABLE "user_characters" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "user_id" uuid NOT NULL,
+    "character_id" varchar(50) NOT NULL,
+    "eidolon" bigint DEFAULT 0,
+    "level" bigint DEFAULT 1,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_users_characters" FOREIGN KEY ("user_id") REFERENCES "users"("id"),
+    CONSTRAINT "fk_characters_user_characters" FOREIGN KEY ("character_id") REFERENCES "characters"("id")
+);
+CREATE INDEX "idx_user_characters_character_id" ON "user_characters" ("character_id");
+CREATE INDEX "idx_user_characters_user_id" ON "user_characters" ("user_id");
+
+CREATE TABLE "banners" (
+    "id" bigserial,
+    "name" varchar(200) NOT NULL,
+    "type" varchar(50),
+    "start_date" timestamptz NOT NULL,
+    "end_date" timestamptz NOT NULL,
+    "image_url" varchar(500),
+    PRIMARY KEY ("id")
+);
+CREATE INDEX "idx_banners_end_date" ON "banners" ("end_date");
+CREATE INDEX "idx_banners_start_date" ON "banners" ("start_date");
+
+CREATE TABLE "banner_characters" (
+    "id" bigserial,
+    "banner_id" bigint NOT NULL,
+    "character_id" varchar(50) NOT NULL,
+    "is_featured" boolean DEFAULT false,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_banner_characters_character" FOREIGN KEY ("character_id") REFERENCES "characters"("id"),
+    CONSTRAINT "fk_banners_characters" FOREIGN KEY ("banner_id") REFERENCES "banners"("id")
+);
+CREATE INDEX "idx_banner_characters_character_id" ON "banner_characters" ("character_id");
+CREATE INDEX "idx_banner_characters_banner_id" ON "banner_characters" ("banner_id");
+
+CREATE TABLE "codes" (
+    "id" bigserial,
+    "code" varchar(50) NOT NULL,
+    "rewards" text,
+    "is_active" boolean DEFAULT true,
+    "expires_at" timestamptz,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX "idx_codes_expires_at" ON "codes" ("expires_at");
+CREATE INDEX "idx_codes_is_active" ON "codes" ("is_active");
+CREATE UNIQUE INDEX "idx_codes_code" ON "codes" ("code");
+
+CREATE TABLE "events" (
+    "id" bigserial,
+    "name" varchar(200) NOT NULL,
+    "type" varchar(50),
+    "description" text,
+    "start_date" timestamptz NOT NULL,
+    "end_date" timestamptz NOT NULL,
+    "image_url" varchar(500),
+    PRIMARY KEY ("id")
+);
+CREATE INDEX "idx_events_end_date" ON "events" ("end_date");
+CREATE INDEX "idx_events_start_date" ON "events" ("start_date");
+
+CREATE UNIQUE INDEX "idx_user_character_unique" ON "user_characters" ("user_id", "character_id");
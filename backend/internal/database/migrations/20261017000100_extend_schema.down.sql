@@ -0,0 +1,39 @@
+-- Drops everything added on top of the baseline
+
+ALTER TABLE "events" DROP COLUMN "rewards";
+ALTER TABLE "events" DROP COLUMN "slug";
+ALTER TABLE "codes" DROP COLUMN "source";
+ALTER TABLE "codes" DROP COLUMN "reward_items";
+ALTER TABLE "banners" DROP COLUMN "phase";
+ALTER TABLE "banners" DROP COLUMN "slug";
+ALTER TABLE "user_characters" DROP COLUMN "superimposition";
+ALTER TABLE "user_characters" DROP COLUMN "light_cone_id";
+ALTER TABLE "users" DROP COLUMN "role";
+
+DROP TABLE "timeline_characters";
+DROP TABLE "timeline_events";
+DROP TABLE "location_connections";
+DROP TABLE "character_relationships";
+DROP TABLE "character_lores";
+DROP TABLE "locations";
+DROP TABLE "factions";
+DROP TABLE "battle_members";
+DROP TABLE "battles";
+DROP TABLE "enemy_variants";
+DROP TABLE "enemy_resistances";
+DROP TABLE "enemy_weaknesses";
+DROP TABLE "enemies";
+DROP TABLE "user_redeemed_codes";
+DROP TABLE "audit_logs";
+DROP TABLE "o_auth_states";
+DROP TABLE "accounts";
+DROP TABLE "refresh_tokens";
+DROP TABLE "verification_tokens";
+DROP TABLE "user_profiles";
+DROP TABLE "activities";
+DROP TABLE "team_preset_members";
+DROP TABLE "team_presets";
+DROP TABLE "pull_plans";
+DROP TABLE "light_cone_superimpositions";
+DROP TABLE "light_cones";
+DROP TABLE "eidolons";
@@ -0,0 +1,393 @@
+-- Tables and columns added on top of the baseline: eidolons, light cones,
+-- accounts and tokens, plans and presets, enemies, battles and lore.
+
+CREATE TABLE "eidolons" (
+    "id" bigserial,
+    "character_id" varchar(50) NOT NULL,
+    "rank" bigint NOT NULL,
+    "name" varchar(100) NOT NULL,
+    "description" text,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_characters_eidolons" FOREIGN KEY ("character_id") REFERENCES "characters"("id")
+);
+CREATE UNIQUE INDEX "idx_character_eidolon_rank" ON "eidolons" ("character_id","rank");
+
+CREATE TABLE "light_cones" (
+    "id" varchar(50),
+    "game_id" varchar(10) NOT NULL,
+    "name" varchar(100) NOT NULL,
+    "rarity" bigint NOT NULL DEFAULT 4,
+    "path_id" bigint NOT NULL,
+    "max_hp" bigint NOT NULL,
+    "max_atk" bigint NOT NULL,
+    "max_def" bigint NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_light_cones_path" FOREIGN KEY ("path_id") REFERENCES "paths"("id")
+);
+CREATE INDEX "idx_light_cones_path_id" ON "light_cones" ("path_id");
+CREATE INDEX "idx_light_cones_rarity" ON "light_cones" ("rarity");
+CREATE UNIQUE INDEX "idx_light_cones_game_id" ON "light_cones" ("game_id");
+
+CREATE TABLE "light_cone_superimpositions" (
+    "id" bigserial,
+    "light_cone_id" varchar(50) NOT NULL,
+    "rank" bigint NOT NULL,
+    "description" text,
+    "properties" jsonb,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_light_cones_superimpositions" FOREIGN KEY ("light_cone_id") REFERENCES "light_cones"("id")
+);
+CREATE UNIQUE INDEX "idx_light_cone_rank" ON "light_cone_superimpositions" ("light_cone_id","rank");
+
+CREATE TABLE "pull_plans" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "user_id" uuid NOT NULL,
+    "name" varchar(100),
+    "banner_type" varchar(20) DEFAULT 'standard',
+    "banner_id" bigint,
+    "current_pity" bigint DEFAULT 0,
+    "is_guaranteed" boolean DEFAULT false,
+    "stellar_jade" bigint DEFAULT 0,
+    "passes" bigint DEFAULT 0,
+    "daily_income" bigint DEFAULT 0,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_pull_plans_user" FOREIGN KEY ("user_id") REFERENCES "users"("id"),
+    CONSTRAINT "fk_pull_plans_banner" FOREIGN KEY ("banner_id") REFERENCES "banners"("id")
+);
+CREATE INDEX "idx_pull_plans_banner_id" ON "pull_plans" ("banner_id");
+CREATE INDEX "idx_pull_plans_user_id" ON "pull_plans" ("user_id");
+
+CREATE TABLE "team_presets" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "user_id" uuid NOT NULL,
+    "name" varchar(100) NOT NULL,
+    "boss_speed" bigint DEFAULT 0,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_team_presets_user" FOREIGN KEY ("user_id") REFERENCES "users"("id")
+);
+CREATE INDEX "idx_team_presets_user_id" ON "team_presets" ("user_id");
+
+CREATE TABLE "team_preset_members" (
+    "id" bigserial,
+    "preset_id" uuid NOT NULL,
+    "slot" bigint NOT NULL,
+    "character_id" varchar(50) NOT NULL,
+    "speed_bonus" bigint DEFAULT 0,
+    "speed_percent" decimal(5,2) DEFAULT 0,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_team_preset_members_character" FOREIGN KEY ("character_id") REFERENCES "characters"("id"),
+    CONSTRAINT "fk_team_presets_members" FOREIGN KEY ("preset_id") REFERENCES "team_presets"("id") ON DELETE CASCADE
+);
+CREATE INDEX "idx_team_preset_members_character_id" ON "team_preset_members" ("character_id");
+CREATE INDEX "idx_team_preset_members_preset_id" ON "team_preset_members" ("preset_id");
+
+CREATE TABLE "activities" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "user_id" uuid NOT NULL,
+    "type" varchar(50) NOT NULL,
+    "data" jsonb,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_activities_user" FOREIGN KEY ("user_id") REFERENCES "users"("id")
+);
+CREATE INDEX "idx_activities_type" ON "activities" ("type");
+CREATE INDEX "idx_activity_user_created" ON "activities" ("user_id","created_at");
+
+CREATE TABLE "user_profiles" (
+    "uid" varchar(20),
+    "data" jsonb NOT NULL,
+    "fetched_at" timestamptz NOT NULL,
+    "expires_at" timestamptz NOT NULL,
+    "stale_until" timestamptz NOT NULL,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("uid")
+);
+CREATE INDEX "idx_user_profiles_expires_at" ON "user_profiles" ("expires_at");
+
+CREATE TABLE "verification_tokens" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "user_id" uuid NOT NULL,
+    "identifier" text NOT NULL,
+    "token_hash" varchar(64) NOT NULL,
+    "type" varchar(20) NOT NULL,
+    "attempts" bigint DEFAULT 0,
+    "expires_at" timestamptz NOT NULL,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_verification_tokens_user" FOREIGN KEY ("user_id") REFERENCES "users"("id")
+);
+CREATE INDEX "idx_verification_tokens_expires_at" ON "verification_tokens" ("expires_at");
+CREATE INDEX "idx_verification_tokens_type" ON "verification_tokens" ("type");
+CREATE UNIQUE INDEX "idx_verification_tokens_token_hash" ON "verification_tokens" ("token_hash");
+CREATE INDEX "idx_verification_tokens_identifier" ON "verification_tokens" ("identifier");
+CREATE INDEX "idx_verification_tokens_user_id" ON "verification_tokens" ("user_id");
+
+CREATE TABLE "refresh_tokens" (
+    "id" uuid,
+    "user_id" uuid NOT NULL,
+    "family_id" uuid NOT NULL,
+    "replaced_by" uuid,
+    "user_agent" varchar(255),
+    "ip" varchar(45),
+    "expires_at" timestamptz NOT NULL,
+    "revoked_at" timestamptz,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_refresh_tokens_user" FOREIGN KEY ("user_id") REFERENCES "users"("id")
+);
+CREATE INDEX "idx_refresh_tokens_expires_at" ON "refresh_tokens" ("expires_at");
+CREATE INDEX "idx_refresh_tokens_family_id" ON "refresh_tokens" ("family_id");
+CREATE INDEX "idx_refresh_tokens_user_id" ON "refresh_tokens" ("user_id");
+
+CREATE TABLE "accounts" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "user_id" uuid NOT NULL,
+    "type" varchar(20) NOT NULL,
+    "provider" varchar(50) NOT NULL,
+    "provider_account_id" text NOT NULL,
+    "email" text,
+    "access_token" text,
+    "refresh_token" text,
+    "id_token" text,
+    "expires_at" timestamptz,
+    "token_type" varchar(20),
+    "scope" text,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_accounts_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX "idx_account_provider" ON "accounts" ("provider","provider_account_id");
+CREATE INDEX "idx_accounts_user_id" ON "accounts" ("user_id");
+
+CREATE TABLE "o_auth_states" (
+    "state" varchar(64),
+    "provider" varchar(50) NOT NULL,
+    "code_verifier" varchar(64) NOT NULL,
+    "user_id" uuid,
+    "redirect_url" varchar(500),
+    "expires_at" timestamptz NOT NULL,
+    "created_at" timestamptz,
+    PRIMARY KEY ("state")
+);
+CREATE INDEX "idx_o_auth_states_expires_at" ON "o_auth_states" ("expires_at");
+
+CREATE TABLE "audit_logs" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "user_id" uuid NOT NULL,
+    "action" varchar(20) NOT NULL,
+    "entity" varchar(50) NOT NULL,
+    "entity_id" varchar(50) NOT NULL,
+    "before" jsonb,
+    "after" jsonb,
+    "ip" varchar(45),
+    "created_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_audit_logs_user" FOREIGN KEY ("user_id") REFERENCES "users"("id")
+);
+CREATE INDEX "idx_audit_logs_created_at" ON "audit_logs" ("created_at");
+CREATE INDEX "idx_audit_entity" ON "audit_logs" ("entity","entity_id");
+CREATE INDEX "idx_audit_logs_user_id" ON "audit_logs" ("user_id");
+
+CREATE TABLE "user_redeemed_codes" (
+    "id" bigserial,
+    "user_id" uuid NOT NULL,
+    "code_id" bigint NOT NULL,
+    "redeemed_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_user_redeemed_codes_user" FOREIGN KEY ("user_id") REFERENCES "users"("id"),
+    CONSTRAINT "fk_user_redeemed_codes_code" FOREIGN KEY ("code_id") REFERENCES "codes"("id")
+);
+CREATE INDEX "idx_user_redeemed_codes_code_id" ON "user_redeemed_codes" ("code_id");
+CREATE UNIQUE INDEX "idx_user_redeemed_code" ON "user_redeemed_codes" ("user_id","code_id");
+
+CREATE TABLE "enemies" (
+    "id" varchar(50),
+    "name" varchar(100) NOT NULL,
+    "type" varchar(20),
+    "hp" bigint NOT NULL,
+    "speed" bigint NOT NULL DEFAULT 100,
+    "def" bigint NOT NULL DEFAULT 1000,
+    "toughness" bigint NOT NULL DEFAULT 120,
+    "image_url" varchar(500),
+    PRIMARY KEY ("id")
+);
+CREATE INDEX "idx_enemies_type" ON "enemies" ("type");
+
+CREATE TABLE "enemy_weaknesses" (
+    "id" bigserial,
+    "enemy_id" varchar(50) NOT NULL,
+    "element_id" bigint NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_enemy_weaknesses_element" FOREIGN KEY ("element_id") REFERENCES "elements"("id"),
+    CONSTRAINT "fk_enemies_weaknesses" FOREIGN KEY ("enemy_id") REFERENCES "enemies"("id")
+);
+CREATE INDEX "idx_enemy_weaknesses_element_id" ON "enemy_weaknesses" ("element_id");
+CREATE INDEX "idx_enemy_weaknesses_enemy_id" ON "enemy_weaknesses" ("enemy_id");
+
+CREATE TABLE "enemy_resistances" (
+    "id" bigserial,
+    "enemy_id" varchar(50) NOT NULL,
+    "element_id" bigint NOT NULL,
+    "value" decimal(4,2) DEFAULT 0,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_enemy_resistances_element" FOREIGN KEY ("element_id") REFERENCES "elements"("id"),
+    CONSTRAINT "fk_enemies_resistances" FOREIGN KEY ("enemy_id") REFERENCES "enemies"("id")
+);
+CREATE INDEX "idx_enemy_resistances_element_id" ON "enemy_resistances" ("element_id");
+CREATE INDEX "idx_enemy_resistances_enemy_id" ON "enemy_resistances" ("enemy_id");
+
+CREATE TABLE "enemy_variants" (
+    "id" bigserial,
+    "enemy_id" varchar(50) NOT NULL,
+    "name" varchar(100) NOT NULL,
+    "level" bigint DEFAULT 90,
+    "hp" bigint NOT NULL,
+    "speed" bigint NOT NULL,
+    "def" bigint NOT NULL,
+    "toughness" bigint NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_enemies_variants" FOREIGN KEY ("enemy_id") REFERENCES "enemies"("id")
+);
+CREATE INDEX "idx_enemy_variants_enemy_id" ON "enemy_variants" ("enemy_id");
+
+CREATE TABLE "battles" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "user_id" uuid NOT NULL,
+    "enemy_id" varchar(50) NOT NULL,
+    "result" varchar(20) NOT NULL,
+    "total_damage" bigint NOT NULL,
+    "turns" bigint NOT NULL,
+    "duration" bigint,
+    "seed" bigint,
+    "max_cycles" bigint DEFAULT 0,
+    "validated" boolean DEFAULT false,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_battles_enemy" FOREIGN KEY ("enemy_id") REFERENCES "enemies"("id"),
+    CONSTRAINT "fk_battles_user" FOREIGN KEY ("user_id") REFERENCES "users"("id")
+);
+CREATE INDEX "idx_battles_validated" ON "battles" ("validated");
+CREATE INDEX "idx_battles_turns" ON "battles" ("turns");
+CREATE INDEX "idx_battles_total_damage" ON "battles" ("total_damage");
+CREATE INDEX "idx_battles_enemy_id" ON "battles" ("enemy_id");
+CREATE INDEX "idx_battles_user_id" ON "battles" ("user_id");
+
+CREATE TABLE "battle_members" (
+    "id" bigserial,
+    "battle_id" uuid NOT NULL,
+    "slot" bigint NOT NULL,
+    "character_id" varchar(50) NOT NULL,
+    "speed" decimal,
+    "atk" decimal,
+    "crit_rate" decimal,
+    "crit_dmg" decimal,
+    "dmg_bonus" decimal,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_battles_team" FOREIGN KEY ("battle_id") REFERENCES "battles"("id") ON DELETE CASCADE
+);
+CREATE INDEX "idx_battle_members_character_id" ON "battle_members" ("character_id");
+CREATE INDEX "idx_battle_members_battle_id" ON "battle_members" ("battle_id");
+
+CREATE TABLE "factions" (
+    "id" varchar(50),
+    "name" varchar(100) NOT NULL,
+    "type" varchar(20),
+    "description" text,
+    "leader" varchar(50),
+    "color" varchar(10),
+    "icon" varchar(20),
+    PRIMARY KEY ("id")
+);
+
+CREATE TABLE "locations" (
+    "id" varchar(50),
+    "name" varchar(100) NOT NULL,
+    "type" varchar(30),
+    "description" text,
+    "areas" jsonb,
+    PRIMARY KEY ("id")
+);
+
+CREATE TABLE "character_lores" (
+    "id" varchar(50),
+    "name" varchar(100) NOT NULL,
+    "title" varchar(100),
+    "bio" text,
+    "element" varchar(20),
+    "path" varchar(30),
+    "faction_id" varchar(50),
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_factions_members" FOREIGN KEY ("faction_id") REFERENCES "factions"("id")
+);
+CREATE INDEX "idx_character_lores_faction_id" ON "character_lores" ("faction_id");
+
+CREATE TABLE "character_relationships" (
+    "id" bigserial,
+    "from_id" varchar(50) NOT NULL,
+    "to_id" varchar(50) NOT NULL,
+    "type" varchar(20) NOT NULL,
+    "label" varchar(100),
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_character_relationships_to" FOREIGN KEY ("to_id") REFERENCES "character_lores"("id"),
+    CONSTRAINT "fk_character_lores_outgoing_relations" FOREIGN KEY ("from_id") REFERENCES "character_lores"("id")
+);
+CREATE INDEX "idx_character_relationships_to_id" ON "character_relationships" ("to_id");
+CREATE UNIQUE INDEX "idx_character_relationship" ON "character_relationships" ("from_id","to_id");
+
+CREATE TABLE "location_connections" (
+    "id" bigserial,
+    "from_id" varchar(50) NOT NULL,
+    "to_id" varchar(50) NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_location_connections_to" FOREIGN KEY ("to_id") REFERENCES "locations"("id"),
+    CONSTRAINT "fk_locations_connections" FOREIGN KEY ("from_id") REFERENCES "locations"("id")
+);
+CREATE INDEX "idx_location_connections_to_id" ON "location_connections" ("to_id");
+CREATE UNIQUE INDEX "idx_location_connection" ON "location_connections" ("from_id","to_id");
+
+CREATE TABLE "timeline_events" (
+    "id" varchar(50),
+    "title" varchar(200) NOT NULL,
+    "chapter" varchar(50),
+    "description" text,
+    "location_id" varchar(50),
+    "sort_order" bigint NOT NULL DEFAULT 0,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_timeline_events_location" FOREIGN KEY ("location_id") REFERENCES "locations"("id")
+);
+CREATE INDEX "idx_timeline_events_order" ON "timeline_events" ("sort_order");
+CREATE INDEX "idx_timeline_events_location_id" ON "timeline_events" ("location_id");
+
+CREATE TABLE "timeline_characters" (
+    "id" bigserial,
+    "event_id" varchar(50) NOT NULL,
+    "character_id" varchar(50) NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_timeline_characters_character" FOREIGN KEY ("character_id") REFERENCES "character_lores"("id"),
+    CONSTRAINT "fk_timeline_events_characters" FOREIGN KEY ("event_id") REFERENCES "timeline_events"("id")
+);
+CREATE INDEX "idx_timeline_characters_character_id" ON "timeline_characters" ("character_id");
+CREATE UNIQUE INDEX "idx_timeline_character" ON "timeline_characters" ("event_id","character_id");
+
+ALTER TABLE "users" ADD COLUMN "role" varchar(20) NOT NULL DEFAULT 'user';
+
+ALTER TABLE "user_characters" ADD COLUMN "light_cone_id" varchar(50);
+ALTER TABLE "user_characters" ADD COLUMN "superimposition" bigint DEFAULT 0;
+ALTER TABLE "user_characters" ADD CONSTRAINT "fk_user_characters_light_cone" FOREIGN KEY ("light_cone_id") REFERENCES "light_cones"("id");
+CREATE INDEX "idx_user_characters_light_cone_id" ON "user_characters" ("light_cone_id");
+
+ALTER TABLE "banners" ADD COLUMN "slug" varchar(50);
+ALTER TABLE "banners" ADD COLUMN "phase" varchar(50);
+CREATE UNIQUE INDEX "idx_banners_slug" ON "banners" ("slug");
+
+ALTER TABLE "codes" ADD COLUMN "reward_items" jsonb;
+ALTER TABLE "codes" ADD COLUMN "source" varchar(100);
+
+ALTER TABLE "events" ADD COLUMN "slug" varchar(50);
+ALTER TABLE "events" ADD COLUMN "rewards" jsonb;
+CREATE UNIQUE INDEX "idx_events_slug" ON "events" ("slug");
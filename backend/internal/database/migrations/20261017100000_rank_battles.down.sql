@@ -0,0 +1,5 @@
+-- Removes battle ranking
+
+DROP INDEX "idx_battles_ranked";
+ALTER TABLE "battles" DROP COLUMN "action_value";
+ALTER TABLE "battles" DROP COLUMN "ranked";
@@ -0,0 +1,15 @@
+-- Only runs replayed from base stats can be ranked, since stat overrides
+-- are whatever the client sent. The replay's action value breaks ties
+-- between runs that took the same number of turns.
+
+ALTER TABLE "battles" ADD COLUMN "ranked" boolean DEFAULT false;
+ALTER TABLE "battles" ADD COLUMN "action_value" decimal;
+CREATE INDEX "idx_battles_ranked" ON "battles" ("ranked");
+
+UPDATE "battles" SET "ranked" = true
+WHERE "validated" AND "result" = 'victory' AND NOT EXISTS (
+    SELECT 1 FROM "battle_members" m
+    WHERE m."battle_id" = "battles"."id"
+      AND (m."speed" IS NOT NULL OR m."atk" IS NOT NULL OR m."crit_rate" IS NOT NULL
+           OR m."crit_dmg" IS NOT NULL OR m."dmg_bonus" IS NOT NULL)
+);
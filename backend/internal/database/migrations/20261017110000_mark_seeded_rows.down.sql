@@ -0,0 +1,15 @@
+-- Removes the seeded markers
+
+ALTER TABLE "timeline_events" DROP COLUMN "seeded";
+ALTER TABLE "character_lores" DROP COLUMN "seeded";
+ALTER TABLE "locations" DROP COLUMN "seeded";
+ALTER TABLE "factions" DROP COLUMN "seeded";
+ALTER TABLE "events" DROP COLUMN "seeded";
+ALTER TABLE "codes" DROP COLUMN "seeded";
+ALTER TABLE "banners" DROP COLUMN "seeded";
+ALTER TABLE "enemies" DROP COLUMN "seeded";
+ALTER TABLE "light_cones" DROP COLUMN "seeded";
+ALTER TABLE "eidolons" DROP COLUMN "seeded";
+ALTER TABLE "character_builds" DROP COLUMN "seeded";
+ALTER TABLE "character_skills" DROP COLUMN "seeded";
+ALTER TABLE "characters" DROP COLUMN "seeded";
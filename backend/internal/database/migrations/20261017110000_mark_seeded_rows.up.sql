@@ -0,0 +1,17 @@
+-- Marks the game data rows seed writes, so pruning leaves rows created
+-- through the admin API alone. Existing rows start unmarked and are marked
+-- the next time seed writes them, so nothing is pruned before then.
+
+ALTER TABLE "characters" ADD COLUMN "seeded" boolean NOT NULL DEFAULT false;
+ALTER TABLE "character_skills" ADD COLUMN "seeded" boolean NOT NULL DEFAULT false;
+ALTER TABLE "character_builds" ADD COLUMN "seeded" boolean NOT NULL DEFAULT false;
+ALTER TABLE "eidolons" ADD COLUMN "seeded" boolean NOT NULL DEFAULT false;
+ALTER TABLE "light_cones" ADD COLUMN "seeded" boolean NOT NULL DEFAULT false;
+ALTER TABLE "enemies" ADD COLUMN "seeded" boolean NOT NULL DEFAULT false;
+ALTER TABLE "banners" ADD COLUMN "seeded" boolean NOT NULL DEFAULT false;
+ALTER TABLE "codes" ADD COLUMN "seeded" boolean NOT NULL DEFAULT false;
+ALTER TABLE "events" ADD COLUMN "seeded" boolean NOT NULL DEFAULT false;
+ALTER TABLE "factions" ADD COLUMN "seeded" boolean NOT NULL DEFAULT false;
+ALTER TABLE "locations" ADD COLUMN "seeded" boolean NOT NULL DEFAULT false;
+ALTER TABLE "character_lores" ADD COLUMN "seeded" boolean NOT NULL DEFAULT false;
+ALTER TABLE "timeline_events" ADD COLUMN "seeded" boolean NOT NULL DEFAULT false;
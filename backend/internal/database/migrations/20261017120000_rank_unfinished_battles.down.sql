@@ -0,0 +1,3 @@
+-- Only ranks cleared runs again
+
+UPDATE "battles" SET "ranked" = false WHERE "result" <> 'victory';
@@ -0,0 +1,10 @@
+-- Runs that didn't clear the enemy rank on the damage board, so ranking
+-- now covers every run replayed from base stats, whatever its result.
+
+UPDATE "battles" SET "ranked" = true
+WHERE "validated" AND "result" <> 'victory' AND NOT EXISTS (
+    SELECT 1 FROM "battle_members" m
+    WHERE m."battle_id" = "battles"."id"
+      AND (m."speed" IS NOT NULL OR m."atk" IS NOT NULL OR m."crit_rate" IS NOT NULL
+           OR m."crit_dmg" IS NOT NULL OR m."dmg_bonus" IS NOT NULL)
+);
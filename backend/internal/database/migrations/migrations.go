@@ -0,0 +1,11 @@
+// Package migrations holds the versioned SQL schema migrations. Each
+// migration is a pair of files named <version>_<name>.up.sql and
+// <version>_<name>.down.sql, where the version is a UTC timestamp.
+package migrations
+
+import "embed"
+
+// FS contains every migration file, built into the binary
+//
+//go:embed *.sql
+var FS embed.FS
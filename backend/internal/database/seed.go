@@ -2,12 +2,16 @@ package database
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
-	"os"
-	"path/filepath"
+	"maps"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/hsr-tools/backend/internal/models"
+	"gorm.io/gorm"
 )
 
 // CharacterJSON represents the JSON structure for characters
@@ -50,209 +54,467 @@ type BuildJSON struct {
 	Sets []string `json:"sets"`
 }
 
-func Seed(dataPath string) error {
+// EnemyJSON represents the JSON structure for enemies
+type EnemyJSON struct {
+	ID         string             `json:"id"`
+	Name       string             `json:"name"`
+	Type       string             `json:"type"`
+	HP         int                `json:"hp"`
+	Speed      int                `json:"speed"`
+	Def        int                `json:"def"`
+	Toughness  int                `json:"toughness"`
+	Weakness   []string           `json:"weakness"`
+	Resistance map[string]float64 `json:"resistance"`
+	ImageURL   string             `json:"imageUrl"`
+	Variants   []EnemyVariantJSON `json:"variants"`
+}
+
+// EnemyVariantJSON represents the JSON structure for enemy variants
+type EnemyVariantJSON struct {
+	Name      string `json:"name"`
+	Level     int    `json:"level"`
+	HP        int    `json:"hp"`
+	Speed     int    `json:"speed"`
+	Def       int    `json:"def"`
+	Toughness int    `json:"toughness"`
+}
+
+// LightConeJSON represents the JSON structure for light cones
+type LightConeJSON struct {
+	ID               string                `json:"id"`
+	GameID           string                `json:"gameId"`
+	Name             string                `json:"name"`
+	Rarity           int                   `json:"rarity"`
+	Path             string                `json:"path"`
+	MaxHP            int                   `json:"maxHp"`
+	MaxAtk           int                   `json:"maxAtk"`
+	MaxDef           int                   `json:"maxDef"`
+	Superimpositions []SuperimpositionJSON `json:"superimpositions"`
+}
+
+// SuperimpositionJSON represents the JSON structure for superimpositions
+type SuperimpositionJSON struct {
+	Rank       int             `json:"rank"`
+	Desc       string          `json:"desc"`
+	Properties json.RawMessage `json:"properties"`
+}
+
+// EidolonJSON represents the JSON structure for eidolons
+type EidolonJSON struct {
+	Rank int    `json:"rank"`
+	Name string `json:"name"`
+	Desc string `json:"desc"`
+}
+
+// BannerJSON represents the JSON structure for banners
+type BannerJSON struct {
+	ID          string   `json:"id"`
+	Phase       string   `json:"phase"`
+	Name        string   `json:"name"`
+	Characters  []string `json:"characters"` // Featured characters, by name
+	FourStars   []string `json:"fourStars"`
+	StartDate   string   `json:"startDate"`
+	EndDate     string   `json:"endDate"`
+	Type        string   `json:"type"`
+	BannerImage string   `json:"bannerImage"`
+}
+
+// CodeJSON represents the JSON structure for redemption codes
+type CodeJSON struct {
+	Code      string  `json:"code"`
+	Rewards   string  `json:"rewards"`
+	Source    string  `json:"source"`
+	AddedAt   string  `json:"addedAt"`
+	ExpiresAt *string `json:"expiresAt"`
+	Status    string  `json:"status"` // "new", "active" or "expired"
+}
+
+// EventJSON represents the JSON structure for events
+type EventJSON struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Type        string   `json:"type"`
+	StartDate   string   `json:"startDate"`
+	EndDate     string   `json:"endDate"`
+	Rewards     []string `json:"rewards"`
+	Description string   `json:"description"`
+	ImageURL    string   `json:"imageUrl"`
+}
+
+// CharacterLoreJSON represents the JSON structure for character lore
+type CharacterLoreJSON struct {
+	ID            string             `json:"id"`
+	Name          string             `json:"name"`
+	Title         string             `json:"title"`
+	Faction       string             `json:"faction"`
+	Element       string             `json:"element"`
+	Path          string             `json:"path"`
+	Bio           string             `json:"bio"`
+	Relationships []RelationshipJSON `json:"relationships"`
+}
+
+// RelationshipJSON represents the JSON structure for lore relationships
+type RelationshipJSON struct {
+	Target string `json:"target"`
+	Type   string `json:"type"`
+	Label  string `json:"label"`
+}
+
+// FactionJSON represents the JSON structure for factions
+type FactionJSON struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Description string `json:"description"`
+	Leader      string `json:"leader"`
+	Color       string `json:"color"`
+	Icon        string `json:"icon"`
+}
+
+// LocationJSON represents the JSON structure for locations
+type LocationJSON struct {
+	ID          string          `json:"id"`
+	Name        string          `json:"name"`
+	Type        string          `json:"type"`
+	Description string          `json:"description"`
+	Areas       json.RawMessage `json:"areas"`
+	ConnectedTo []string        `json:"connectedTo"`
+}
+
+// TimelineEventJSON represents the JSON structure for timeline events
+type TimelineEventJSON struct {
+	ID          string   `json:"id"`
+	Title       string   `json:"title"`
+	Location    string   `json:"location"`
+	Chapter     string   `json:"chapter"`
+	Description string   `json:"description"`
+	Characters  []string `json:"characters"`
+}
+
+// SeedOptions controls how Seed applies the data files
+type SeedOptions struct {
+	DryRun bool // Report what would change, then roll everything back
+	Prune  bool // Delete rows that are no longer in the data files
+}
+
+// errDryRun rolls back the seed transaction once a dry run has its report
+var errDryRun = errors.New("dry run")
+
+// Seed validates every data file, then applies them in one transaction, so
+// a failure part way leaves the database as it was. Running it twice with
+// the same data changes nothing.
+func Seed(dataPath string, opts SeedOptions) error {
 	log.Println("🌱 Starting database seeding...")
 
-	// Seed elements
-	if err := seedElements(); err != nil {
-		return fmt.Errorf("failed to seed elements: %w", err)
+	data, problems := loadSeedData(dataPath)
+	problems.log()
+	if err := problems.err(); err != nil {
+		return err
 	}
 
-	// Seed paths
-	if err := seedPaths(); err != nil {
-		return fmt.Errorf("failed to seed paths: %w", err)
-	}
+	report := &seedReport{}
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		s := &seeder{tx: tx, data: data, opts: opts, report: report}
 
-	// Seed characters from JSON
-	if err := seedCharacters(dataPath); err != nil {
-		return fmt.Errorf("failed to seed characters: %w", err)
-	}
+		// Seed elements
+		if err := s.seedElements(); err != nil {
+			return fmt.Errorf("failed to seed elements: %w", err)
+		}
 
-	// Seed skills from JSON
-	if err := seedSkills(dataPath); err != nil {
-		return fmt.Errorf("failed to seed skills: %w", err)
-	}
+		// Seed paths
+		if err := s.seedPaths(); err != nil {
+			return fmt.Errorf("failed to seed paths: %w", err)
+		}
+
+		// Seed characters from JSON
+		if err := s.seedCharacters(); err != nil {
+			return fmt.Errorf("failed to seed characters: %w", err)
+		}
+
+		// Seed light cones from JSON
+		if err := s.seedLightCones(); err != nil {
+			return fmt.Errorf("failed to seed light cones: %w", err)
+		}
+
+		// Seed skills from JSON
+		if err := s.seedSkills(); err != nil {
+			return fmt.Errorf("failed to seed skills: %w", err)
+		}
+
+		// Seed eidolons from JSON
+		if err := s.seedEidolons(); err != nil {
+			return fmt.Errorf("failed to seed eidolons: %w", err)
+		}
+
+		// Seed builds from JSON
+		if err := s.seedBuilds(); err != nil {
+			return fmt.Errorf("failed to seed builds: %w", err)
+		}
+
+		// Seed enemies from JSON
+		if err := s.seedEnemies(); err != nil {
+			return fmt.Errorf("failed to seed enemies: %w", err)
+		}
+
+		// Seed banners from JSON
+		if err := s.seedBanners(); err != nil {
+			return fmt.Errorf("failed to seed banners: %w", err)
+		}
 
-	// Seed builds from JSON
-	if err := seedBuilds(dataPath); err != nil {
-		return fmt.Errorf("failed to seed builds: %w", err)
+		// Seed redemption codes from JSON
+		if err := s.seedCodes(); err != nil {
+			return fmt.Errorf("failed to seed codes: %w", err)
+		}
+
+		// Seed events from JSON
+		if err := s.seedEvents(); err != nil {
+			return fmt.Errorf("failed to seed events: %w", err)
+		}
+
+		// Seed lore graph from JSON
+		if err := s.seedLore(); err != nil {
+			return fmt.Errorf("failed to seed lore: %w", err)
+		}
+
+		// Remove rows the data files no longer have
+		if opts.Prune {
+			if err := s.pruneStale(); err != nil {
+				return err
+			}
+		}
+
+		if opts.DryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		return err
 	}
 
+	report.log(opts.DryRun)
+	if opts.DryRun {
+		log.Println("✅ Dry run completed, no changes were made")
+		return nil
+	}
 	log.Println("✅ Database seeding completed")
 	return nil
 }
 
-func seedElements() error {
-	elements := []models.Element{
-		{Name: "Physical", IconURL: "https://raw.githubusercontent.com/Mar-7th/StarRailRes/master/icon/element/Physical.png"},
-		{Name: "Fire", IconURL: "https://raw.githubusercontent.com/Mar-7th/StarRailRes/master/icon/element/Fire.png"},
-		{Name: "Ice", IconURL: "https://raw.githubusercontent.com/Mar-7th/StarRailRes/master/icon/element/Ice.png"},
-		{Name: "Lightning", IconURL: "https://raw.githubusercontent.com/Mar-7th/StarRailRes/master/icon/element/Thunder.png"},
-		{Name: "Wind", IconURL: "https://raw.githubusercontent.com/Mar-7th/StarRailRes/master/icon/element/Wind.png"},
-		{Name: "Quantum", IconURL: "https://raw.githubusercontent.com/Mar-7th/StarRailRes/master/icon/element/Quantum.png"},
-		{Name: "Imaginary", IconURL: "https://raw.githubusercontent.com/Mar-7th/StarRailRes/master/icon/element/Imaginary.png"},
+// lastEntries drops all but the last entry for each key, keeping file order
+func lastEntries[T any](items []T, key func(T) string) []T {
+	last := make(map[string]int, len(items))
+	for i, item := range items {
+		last[key(item)] = i
 	}
-
-	for _, elem := range elements {
-		result := DB.Where("name = ?", elem.Name).FirstOrCreate(&elem)
-		if result.Error != nil {
-			return result.Error
+	unique := make([]T, 0, len(last))
+	for i, item := range items {
+		if last[key(item)] == i {
+			unique = append(unique, item)
 		}
 	}
-	log.Printf("   ✓ Seeded %d elements", len(elements))
-	return nil
+	return unique
 }
 
-func seedPaths() error {
-	paths := []models.Path{
-		{Name: "Destruction", IconURL: "https://raw.githubusercontent.com/Mar-7th/StarRailRes/master/icon/path/Destruction.png"},
-		{Name: "The Hunt", IconURL: "https://raw.githubusercontent.com/Mar-7th/StarRailRes/master/icon/path/Hunt.png"},
-		{Name: "Erudition", IconURL: "https://raw.githubusercontent.com/Mar-7th/StarRailRes/master/icon/path/Erudition.png"},
-		{Name: "Harmony", IconURL: "https://raw.githubusercontent.com/Mar-7th/StarRailRes/master/icon/path/Harmony.png"},
-		{Name: "Nihility", IconURL: "https://raw.githubusercontent.com/Mar-7th/StarRailRes/master/icon/path/Nihility.png"},
-		{Name: "Preservation", IconURL: "https://raw.githubusercontent.com/Mar-7th/StarRailRes/master/icon/path/Preservation.png"},
-		{Name: "Abundance", IconURL: "https://raw.githubusercontent.com/Mar-7th/StarRailRes/master/icon/path/Abundance.png"},
-		{Name: "Remembrance", IconURL: "https://raw.githubusercontent.com/Mar-7th/StarRailRes/master/icon/path/Remembrance.png"},
-	}
+var defaultElements = []models.Element{
+	{Name: "Physical", IconURL: "https://raw.githubusercontent.com/Mar-7th/StarRailRes/master/icon/element/Physical.png"},
+	{Name: "Fire", IconURL: "https://raw.githubusercontent.com/Mar-7th/StarRailRes/master/icon/element/Fire.png"},
+	{Name: "Ice", IconURL: "https://raw.githubusercontent.com/Mar-7th/StarRailRes/master/icon/element/Ice.png"},
+	{Name: "Lightning", IconURL: "https://raw.githubusercontent.com/Mar-7th/StarRailRes/master/icon/element/Thunder.png"},
+	{Name: "Wind", IconURL: "https://raw.githubusercontent.com/Mar-7th/StarRailRes/master/icon/element/Wind.png"},
+	{Name: "Quantum", IconURL: "https://raw.githubusercontent.com/Mar-7th/StarRailRes/master/icon/element/Quantum.png"},
+	{Name: "Imaginary", IconURL: "https://raw.githubusercontent.com/Mar-7th/StarRailRes/master/icon/element/Imaginary.png"},
+}
 
-	for _, p := range paths {
-		result := DB.Where("name = ?", p.Name).FirstOrCreate(&p)
-		if result.Error != nil {
-			return result.Error
+var defaultPaths = []models.Path{
+	{Name: "Destruction", IconURL: "https://raw.githubusercontent.com/Mar-7th/StarRailRes/master/icon/path/Destruction.png"},
+	{Name: "The Hunt", IconURL: "https://raw.githubusercontent.com/Mar-7th/StarRailRes/master/icon/path/Hunt.png"},
+	{Name: "Erudition", IconURL: "https://raw.githubusercontent.com/Mar-7th/StarRailRes/master/icon/path/Erudition.png"},
+	{Name: "Harmony", IconURL: "https://raw.githubusercontent.com/Mar-7th/StarRailRes/master/icon/path/Harmony.png"},
+	{Name: "Nihility", IconURL: "https://raw.githubusercontent.com/Mar-7th/StarRailRes/master/icon/path/Nihility.png"},
+	{Name: "Preservation", IconURL: "https://raw.githubusercontent.com/Mar-7th/StarRailRes/master/icon/path/Preservation.png"},
+	{Name: "Abundance", IconURL: "https://raw.githubusercontent.com/Mar-7th/StarRailRes/master/icon/path/Abundance.png"},
+	{Name: "Remembrance", IconURL: "https://raw.githubusercontent.com/Mar-7th/StarRailRes/master/icon/path/Remembrance.png"},
+}
+
+func (s *seeder) seedElements() error {
+	s.file = "elements"
+	for _, elem := range defaultElements {
+		if err := s.upsert("elements", elem.Name, &elem, "name = ?", elem.Name); err != nil {
+			return err
 		}
 	}
-	log.Printf("   ✓ Seeded %d paths", len(paths))
 	return nil
 }
 
-func seedCharacters(dataPath string) error {
-	file, err := os.ReadFile(filepath.Join(dataPath, "characters.json"))
-	if err != nil {
-		return fmt.Errorf("failed to read characters.json: %w", err)
+func (s *seeder) seedPaths() error {
+	s.file = "paths"
+	for _, p := range defaultPaths {
+		if err := s.upsert("paths", p.Name, &p, "name = ?", p.Name); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
-	var characters []CharacterJSON
-	if err := json.Unmarshal(file, &characters); err != nil {
-		return fmt.Errorf("failed to parse characters.json: %w", err)
+// nameIDs maps element or path names to their IDs
+func (s *seeder) nameIDs(table string) (map[string]int, error) {
+	var rows []struct {
+		ID   int
+		Name string
 	}
-
-	// Get element and path maps
-	elementMap := make(map[string]int)
-	pathMap := make(map[string]int)
-
-	var elements []models.Element
-	DB.Find(&elements)
-	for _, e := range elements {
-		elementMap[e.Name] = e.ID
+	if err := s.tx.Table(table).Select("id", "name").Find(&rows).Error; err != nil {
+		return nil, err
 	}
-
-	var paths []models.Path
-	DB.Find(&paths)
-	for _, p := range paths {
-		pathMap[p.Name] = p.ID
+	ids := make(map[string]int, len(rows))
+	for _, row := range rows {
+		ids[row.Name] = row.ID
 	}
+	return ids, nil
+}
 
-	count := 0
-	for _, c := range characters {
-		elementID, ok := elementMap[c.Element]
-		if !ok {
-			log.Printf("   ⚠ Unknown element '%s' for character '%s'", c.Element, c.Name)
-			continue
-		}
+func (s *seeder) seedCharacters() error {
+	s.file = "characters.json"
 
-		pathID, ok := pathMap[c.Path]
-		if !ok {
-			log.Printf("   ⚠ Unknown path '%s' for character '%s'", c.Path, c.Name)
-			continue
-		}
+	elementMap, err := s.nameIDs("elements")
+	if err != nil {
+		return err
+	}
+	pathMap, err := s.nameIDs("paths")
+	if err != nil {
+		return err
+	}
 
+	for _, c := range lastEntries(s.data.Characters, func(c CharacterJSON) string { return c.ID }) {
 		char := models.Character{
 			ID:           c.ID,
 			CharID:       c.CharID,
 			Name:         c.Name,
-			ElementID:    elementID,
-			PathID:       pathID,
+			ElementID:    elementMap[c.Element],
+			PathID:       pathMap[c.Path],
 			Rarity:       c.Rarity,
 			BaseSpeed:    c.BaseSpeed,
 			ReleaseOrder: c.ReleaseOrder,
 		}
-
-		result := DB.Where("id = ?", char.ID).Assign(char).FirstOrCreate(&char)
-		if result.Error != nil {
-			log.Printf("   ⚠ Failed to seed character '%s': %v", c.Name, result.Error)
-			continue
+		if err := s.upsert("characters", c.ID, &char, "id = ?", c.ID); err != nil {
+			return err
 		}
-		count++
 	}
-
-	log.Printf("   ✓ Seeded %d characters", count)
 	return nil
 }
 
-func seedSkills(dataPath string) error {
-	file, err := os.ReadFile(filepath.Join(dataPath, "skills.json"))
+func (s *seeder) seedLightCones() error {
+	s.file = "light-cones.json"
+
+	pathMap, err := s.nameIDs("paths")
 	if err != nil {
-		return fmt.Errorf("failed to read skills.json: %w", err)
+		return err
 	}
 
-	var skillsMap map[string]SkillJSON
-	if err := json.Unmarshal(file, &skillsMap); err != nil {
-		return fmt.Errorf("failed to parse skills.json: %w", err)
+	for _, lc := range lastEntries(s.data.LightCones, func(lc LightConeJSON) string { return lc.ID }) {
+		lightCone := models.LightCone{
+			ID:     lc.ID,
+			GameID: lc.GameID,
+			Name:   lc.Name,
+			Rarity: lc.Rarity,
+			PathID: pathMap[lc.Path],
+			MaxHP:  lc.MaxHP,
+			MaxAtk: lc.MaxAtk,
+			MaxDef: lc.MaxDef,
+		}
+		if err := s.upsert("light_cones", lc.ID, &lightCone, "id = ?", lc.ID); err != nil {
+			return err
+		}
+
+		// Seed superimpositions
+		ranks := make([]interface{}, 0, len(lc.Superimpositions))
+		for _, si := range lc.Superimpositions {
+			superimposition := models.LightConeSuperimposition{
+				LightConeID: lc.ID,
+				Rank:        si.Rank,
+				Description: si.Desc,
+				Properties:  si.Properties,
+			}
+			key := fmt.Sprintf("%s/%d", lc.ID, si.Rank)
+			if err := s.upsert("light_cone_superimpositions", key, &superimposition, "light_cone_id = ? AND rank = ?", lc.ID, si.Rank); err != nil {
+				return err
+			}
+			ranks = append(ranks, si.Rank)
+		}
+		if err := s.removeStale("light_cone_superimpositions", lc.ID, "light_cone_id", lc.ID, "rank", ranks); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
-	count := 0
-	for charID, s := range skillsMap {
-		// Check if character exists
-		var char models.Character
-		if DB.Where("id = ?", charID).First(&char).Error != nil {
-			continue // Skip if character doesn't exist
+func (s *seeder) seedSkills() error {
+	s.file = "skills.json"
+
+	for _, charID := range slices.Sorted(maps.Keys(s.data.Skills)) {
+		if !s.data.hasCharacter(charID) {
+			continue // Reported by validation
 		}
+		sk := s.data.Skills[charID]
 
 		skill := models.CharacterSkill{
 			CharacterID:     charID,
-			BasicMultiplier: s.BasicMultiplier,
-			SkillMultiplier: s.SkillMultiplier,
-			UltMultiplier:   s.UltMultiplier,
-			BasicEnergy:     s.BasicEnergy,
-			SkillEnergy:     s.SkillEnergy,
-			UltCost:         s.UltCost,
-			UltType:         s.UltType,
-			Passive:         s.Passive,
-			BaseAtk:         s.BaseAtk,
-			BaseCritRate:    s.BaseCritRate,
-			BaseCritDmg:     s.BaseCritDmg,
-		}
-
-		result := DB.Where("character_id = ?", charID).Assign(skill).FirstOrCreate(&skill)
-		if result.Error != nil {
-			log.Printf("   ⚠ Failed to seed skill for '%s': %v", charID, result.Error)
-			continue
+			BasicMultiplier: sk.BasicMultiplier,
+			SkillMultiplier: sk.SkillMultiplier,
+			UltMultiplier:   sk.UltMultiplier,
+			BasicEnergy:     sk.BasicEnergy,
+			SkillEnergy:     sk.SkillEnergy,
+			UltCost:         sk.UltCost,
+			UltType:         sk.UltType,
+			Passive:         sk.Passive,
+			BaseAtk:         sk.BaseAtk,
+			BaseCritRate:    sk.BaseCritRate,
+			BaseCritDmg:     sk.BaseCritDmg,
+		}
+		if err := s.upsert("character_skills", charID, &skill, "character_id = ?", charID); err != nil {
+			return err
 		}
-		count++
 	}
-
-	log.Printf("   ✓ Seeded %d character skills", count)
 	return nil
 }
 
-func seedBuilds(dataPath string) error {
-	file, err := os.ReadFile(filepath.Join(dataPath, "optimal-builds.json"))
-	if err != nil {
-		return fmt.Errorf("failed to read optimal-builds.json: %w", err)
-	}
+func (s *seeder) seedEidolons() error {
+	s.file = "eidolons.json"
+
+	for _, charID := range slices.Sorted(maps.Keys(s.data.Eidolons)) {
+		if !s.data.hasCharacter(charID) {
+			continue
+		}
 
-	var buildsMap map[string]BuildJSON
-	if err := json.Unmarshal(file, &buildsMap); err != nil {
-		return fmt.Errorf("failed to parse optimal-builds.json: %w", err)
+		ranks := []interface{}{}
+		for _, e := range s.data.Eidolons[charID] {
+			eidolon := models.Eidolon{
+				CharacterID: charID,
+				Rank:        e.Rank,
+				Name:        e.Name,
+				Description: e.Desc,
+			}
+			key := fmt.Sprintf("%s/%d", charID, e.Rank)
+			if err := s.upsert("eidolons", key, &eidolon, "character_id = ? AND rank = ?", charID, e.Rank); err != nil {
+				return err
+			}
+			ranks = append(ranks, e.Rank)
+		}
+		if err := s.removeStale("eidolons", charID, "character_id", charID, "rank", ranks); err != nil {
+			return err
+		}
 	}
+	return nil
+}
+
+func (s *seeder) seedBuilds() error {
+	s.file = "optimal-builds.json"
 
-	count := 0
-	for charID, b := range buildsMap {
-		// Check if character exists
-		var char models.Character
-		if DB.Where("id = ?", charID).First(&char).Error != nil {
+	for _, charID := range slices.Sorted(maps.Keys(s.data.Builds)) {
+		if !s.data.hasCharacter(charID) {
 			continue
 		}
+		b := s.data.Builds[charID]
 
 		build := models.CharacterBuild{
 			CharacterID: charID,
@@ -261,39 +523,504 @@ func seedBuilds(dataPath string) error {
 			OrbMain:     b.MainStats.Orb,
 			RopeMain:    b.MainStats.Rope,
 		}
-
-		result := DB.Where("character_id = ?", charID).Assign(build).FirstOrCreate(&build)
-		if result.Error != nil {
-			log.Printf("   ⚠ Failed to seed build for '%s': %v", charID, result.Error)
-			continue
+		if err := s.upsert("character_builds", charID, &build, "character_id = ?", charID); err != nil {
+			return err
 		}
 
-		// Seed substats
-		for stat, weight := range b.Substats {
+		// Seed substats, dropping any the build no longer lists
+		stats := []interface{}{}
+		for _, stat := range slices.Sorted(maps.Keys(b.Substats)) {
 			substat := models.CharacterBuildSubstat{
 				BuildID:  build.ID,
 				StatName: stat,
-				Weight:   weight,
+				Weight:   b.Substats[stat],
 			}
-			DB.Where("build_id = ? AND stat_name = ?", build.ID, stat).Assign(substat).FirstOrCreate(&substat)
+			if err := s.upsert("character_build_substats", charID+"/"+stat, &substat, "build_id = ? AND stat_name = ?", build.ID, stat); err != nil {
+				return err
+			}
+			stats = append(stats, stat)
+		}
+		if err := s.removeStale("character_build_substats", charID, "build_id", build.ID, "stat_name", stats); err != nil {
+			return err
 		}
 
-		// Seed recommended sets
+		// Seed recommended sets in priority order
+		setIDs := []interface{}{}
 		for i, setName := range b.Sets {
-			var set models.RelicSet
-			DB.Where("name = ?", setName).FirstOrCreate(&set, models.RelicSet{Name: setName})
-
+			set, err := s.relicSet(setName)
+			if err != nil {
+				return err
+			}
 			buildSet := models.CharacterBuildSet{
 				BuildID:    build.ID,
 				RelicSetID: set.ID,
 				Priority:   i + 1,
 			}
-			DB.Where("build_id = ? AND relic_set_id = ?", build.ID, set.ID).Assign(buildSet).FirstOrCreate(&buildSet)
+			if err := s.upsert("character_build_sets", charID+"/"+setName, &buildSet, "build_id = ? AND relic_set_id = ?", build.ID, set.ID); err != nil {
+				return err
+			}
+			setIDs = append(setIDs, set.ID)
+		}
+		if err := s.removeStale("character_build_sets", charID, "build_id", build.ID, "relic_set_id", setIDs); err != nil {
+			return err
 		}
+	}
+	return nil
+}
 
-		count++
+// relicSet finds a relic set by name, creating it if builds are the first
+// to mention it. Existing sets are left alone since only the name is known.
+func (s *seeder) relicSet(name string) (models.RelicSet, error) {
+	var set models.RelicSet
+	if err := s.tx.Where("name = ?", name).Limit(1).Find(&set).Error; err != nil {
+		return set, err
+	}
+	if set.ID != 0 {
+		return set, nil
 	}
 
-	log.Printf("   ✓ Seeded %d character builds", count)
+	set = models.RelicSet{Name: name}
+	if err := s.tx.Create(&set).Error; err != nil {
+		return set, err
+	}
+	diff := s.diff("relic_sets")
+	diff.Created = append(diff.Created, name)
+	return set, nil
+}
+
+// defaultToughness is used when enemies.json doesn't list a toughness bar
+func defaultToughness(enemyType string) int {
+	switch enemyType {
+	case "boss":
+		return 300
+	case "elite":
+		return 160
+	default:
+		return 120
+	}
+}
+
+func (s *seeder) seedEnemies() error {
+	s.file = "enemies.json"
+
+	elementMap, err := s.nameIDs("elements")
+	if err != nil {
+		return err
+	}
+
+	for _, e := range lastEntries(s.data.Enemies, func(e EnemyJSON) string { return e.ID }) {
+		toughness := e.Toughness
+		if toughness == 0 {
+			toughness = defaultToughness(e.Type)
+		}
+
+		enemy := models.Enemy{
+			ID:        e.ID,
+			Name:      e.Name,
+			Type:      e.Type,
+			HP:        e.HP,
+			Speed:     e.Speed,
+			Def:       e.Def,
+			Toughness: toughness,
+			ImageURL:  e.ImageURL,
+		}
+		if err := s.upsert("enemies", e.ID, &enemy, "id = ?", e.ID); err != nil {
+			return err
+		}
+
+		// Seed weaknesses
+		weaknesses := []interface{}{}
+		for _, elem := range e.Weakness {
+			elementID := elementMap[elem]
+			weakness := models.EnemyWeakness{EnemyID: e.ID, ElementID: elementID}
+			if err := s.upsert("enemy_weaknesses", e.ID+"/"+elem, &weakness, "enemy_id = ? AND element_id = ?", e.ID, elementID); err != nil {
+				return err
+			}
+			weaknesses = append(weaknesses, elementID)
+		}
+		if err := s.removeStale("enemy_weaknesses", e.ID, "enemy_id", e.ID, "element_id", weaknesses); err != nil {
+			return err
+		}
+
+		// Seed resistances
+		resistances := []interface{}{}
+		for _, elem := range slices.Sorted(maps.Keys(e.Resistance)) {
+			elementID := elementMap[elem]
+			resistance := models.EnemyResistance{EnemyID: e.ID, ElementID: elementID, Value: e.Resistance[elem]}
+			if err := s.upsert("enemy_resistances", e.ID+"/"+elem, &resistance, "enemy_id = ? AND element_id = ?", e.ID, elementID); err != nil {
+				return err
+			}
+			resistances = append(resistances, elementID)
+		}
+		if err := s.removeStale("enemy_resistances", e.ID, "enemy_id", e.ID, "element_id", resistances); err != nil {
+			return err
+		}
+
+		// Seed variants
+		variants := []interface{}{}
+		for _, v := range e.Variants {
+			variant := models.EnemyVariant{
+				EnemyID:   e.ID,
+				Name:      v.Name,
+				Level:     v.Level,
+				HP:        v.HP,
+				Speed:     v.Speed,
+				Def:       v.Def,
+				Toughness: v.Toughness,
+			}
+			if variant.Level == 0 {
+				variant.Level = 90
+			}
+			if variant.Toughness == 0 {
+				variant.Toughness = toughness
+			}
+			if err := s.upsert("enemy_variants", e.ID+"/"+v.Name, &variant, "enemy_id = ? AND name = ?", e.ID, v.Name); err != nil {
+				return err
+			}
+			variants = append(variants, v.Name)
+		}
+		if err := s.removeStale("enemy_variants", e.ID, "enemy_id", e.ID, "name", variants); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseDate parses an RFC 3339 date from a data file. Bad dates are errors
+// rather than warnings, since a zero date would silently break banner and
+// event windows.
+func parseDate(file, id, field, value string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s: invalid %s %q for '%s': %w", file, field, value, id, err)
+	}
+	return t, nil
+}
+
+// parseDateRange parses a start and end date and checks their order
+func parseDateRange(file, id, startValue, endValue string) (time.Time, time.Time, error) {
+	start, err := parseDate(file, id, "startDate", startValue)
+	if err != nil {
+		return start, start, err
+	}
+	end, err := parseDate(file, id, "endDate", endValue)
+	if err != nil {
+		return start, end, err
+	}
+	if end.Before(start) {
+		return start, end, fmt.Errorf("%s: endDate is before startDate for '%s'", file, id)
+	}
+	return start, end, nil
+}
+
+func (s *seeder) seedBanners() error {
+	s.file = "banners.json"
+
+	// Banners list characters by display name
+	var characters []models.Character
+	if err := s.tx.Find(&characters).Error; err != nil {
+		return err
+	}
+	charByName := make(map[string]string, len(characters))
+	for _, char := range characters {
+		charByName[char.Name] = char.ID
+	}
+
+	for _, b := range lastEntries(s.data.Banners, func(b BannerJSON) string { return b.ID }) {
+		start, end, err := parseDateRange(s.file, b.ID, b.StartDate, b.EndDate)
+		if err != nil {
+			return err
+		}
+
+		slug := b.ID
+		banner := models.Banner{
+			Slug:      &slug,
+			Name:      b.Name,
+			Phase:     b.Phase,
+			Type:      b.Type,
+			StartDate: start,
+			EndDate:   end,
+			ImageURL:  b.BannerImage,
+		}
+		if err := s.upsert("banners", b.ID, &banner, "slug = ?", b.ID); err != nil {
+			return err
+		}
+
+		// Four-stars are listed on the banner but not featured
+		linked := []interface{}{}
+		for _, group := range []struct {
+			names    []string
+			featured bool
+		}{{b.Characters, true}, {b.FourStars, false}} {
+			for _, name := range group.names {
+				charID, ok := charByName[name]
+				if !ok {
+					continue
+				}
+				link := models.BannerCharacter{
+					BannerID:    banner.ID,
+					CharacterID: charID,
+					IsFeatured:  group.featured,
+				}
+				if err := s.upsert("banner_characters", b.ID+"/"+charID, &link, "banner_id = ? AND character_id = ?", banner.ID, charID); err != nil {
+					return err
+				}
+				linked = append(linked, charID)
+			}
+		}
+		if err := s.removeStale("banner_characters", b.ID, "banner_id", banner.ID, "character_id", linked); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *seeder) seedCodes() error {
+	s.file = "codes.json"
+
+	for _, c := range lastEntries(s.data.Codes, func(c CodeJSON) string { return strings.ToUpper(c.Code) }) {
+		code := models.Code{
+			Code:        c.Code,
+			Rewards:     c.Rewards,
+			RewardItems: models.ParseRewards(c.Rewards),
+			Source:      c.Source,
+			IsActive:    c.Status != "expired",
+		}
+		if c.AddedAt != "" {
+			added, err := parseDate(s.file, c.Code, "addedAt", c.AddedAt)
+			if err != nil {
+				return err
+			}
+			code.CreatedAt = added
+		}
+		if c.ExpiresAt != nil {
+			expires, err := parseDate(s.file, c.Code, "expiresAt", *c.ExpiresAt)
+			if err != nil {
+				return err
+			}
+			code.ExpiresAt = &expires
+			if expires.Before(time.Now()) {
+				code.IsActive = false
+			}
+		}
+		if err := s.upsert("codes", c.Code, &code, "code = ?", c.Code); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *seeder) seedEvents() error {
+	s.file = "events.json"
+
+	for _, e := range lastEntries(s.data.Events, func(e EventJSON) string { return e.ID }) {
+		start, end, err := parseDateRange(s.file, e.ID, e.StartDate, e.EndDate)
+		if err != nil {
+			return err
+		}
+
+		slug := e.ID
+		event := models.Event{
+			Slug:        &slug,
+			Name:        e.Name,
+			Type:        e.Type,
+			Description: e.Description,
+			Rewards:     e.Rewards,
+			StartDate:   start,
+			EndDate:     end,
+			ImageURL:    e.ImageURL,
+		}
+		if err := s.upsert("events", e.ID, &event, "slug = ?", e.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *seeder) seedLore() error {
+	d := s.data
+
+	s.file = "lore/factions.json"
+	for _, id := range slices.Sorted(maps.Keys(d.Factions)) {
+		f := d.Factions[id]
+		faction := models.Faction{
+			ID:          f.ID,
+			Name:        f.Name,
+			Type:        f.Type,
+			Description: f.Description,
+			Leader:      f.Leader,
+			Color:       f.Color,
+			Icon:        f.Icon,
+		}
+		if err := s.upsert("factions", id, &faction, "id = ?", id); err != nil {
+			return err
+		}
+	}
+
+	s.file = "lore/locations.json"
+	for _, id := range slices.Sorted(maps.Keys(d.Locations)) {
+		l := d.Locations[id]
+		location := models.Location{
+			ID:          l.ID,
+			Name:        l.Name,
+			Type:        l.Type,
+			Description: l.Description,
+			Areas:       l.Areas,
+		}
+		if err := s.upsert("locations", id, &location, "id = ?", id); err != nil {
+			return err
+		}
+	}
+
+	s.file = "lore/characters-lore.json"
+	for _, id := range slices.Sorted(maps.Keys(d.Lore)) {
+		c := d.Lore[id]
+		lore := models.CharacterLore{
+			ID:      c.ID,
+			Name:    c.Name,
+			Title:   c.Title,
+			Bio:     c.Bio,
+			Element: c.Element,
+			Path:    c.Path,
+		}
+		if _, ok := d.Factions[c.Faction]; ok {
+			factionID := c.Faction
+			lore.FactionID = &factionID
+		}
+		if err := s.upsert("character_lores", id, &lore, "id = ?", id); err != nil {
+			return err
+		}
+	}
+
+	// Relationships and connections need both endpoints seeded first
+	for _, id := range slices.Sorted(maps.Keys(d.Lore)) {
+		targets := []interface{}{}
+		for _, r := range d.Lore[id].Relationships {
+			if _, ok := d.Lore[r.Target]; !ok {
+				continue
+			}
+			rel := models.CharacterRelationship{
+				FromID: id,
+				ToID:   r.Target,
+				Type:   r.Type,
+				Label:  r.Label,
+			}
+			if err := s.upsert("character_relationships", id+" -> "+r.Target, &rel, "from_id = ? AND to_id = ?", id, r.Target); err != nil {
+				return err
+			}
+			targets = append(targets, r.Target)
+		}
+		if err := s.removeStale("character_relationships", id, "from_id", id, "to_id", targets); err != nil {
+			return err
+		}
+	}
+
+	s.file = "lore/locations.json"
+	for _, id := range slices.Sorted(maps.Keys(d.Locations)) {
+		targets := []interface{}{}
+		for _, target := range d.Locations[id].ConnectedTo {
+			if _, ok := d.Locations[target]; !ok {
+				continue
+			}
+			conn := models.LocationConnection{FromID: id, ToID: target}
+			if err := s.upsert("location_connections", id+" -> "+target, &conn, "from_id = ? AND to_id = ?", id, target); err != nil {
+				return err
+			}
+			targets = append(targets, target)
+		}
+		if err := s.removeStale("location_connections", id, "from_id", id, "to_id", targets); err != nil {
+			return err
+		}
+	}
+
+	s.file = "lore/timeline.json"
+	for i, e := range d.Timeline {
+		event := models.TimelineEvent{
+			ID:          e.ID,
+			Title:       e.Title,
+			Chapter:     e.Chapter,
+			Description: e.Description,
+			Order:       i + 1,
+		}
+		if _, ok := d.Locations[e.Location]; ok {
+			locationID := e.Location
+			event.LocationID = &locationID
+		}
+		if err := s.upsert("timeline_events", e.ID, &event, "id = ?", e.ID); err != nil {
+			return err
+		}
+
+		charIDs := []interface{}{}
+		for _, charID := range e.Characters {
+			if _, ok := d.Lore[charID]; !ok {
+				continue
+			}
+			link := models.TimelineCharacter{EventID: e.ID, CharacterID: charID}
+			if err := s.upsert("timeline_characters", e.ID+"/"+charID, &link, "event_id = ? AND character_id = ?", e.ID, charID); err != nil {
+				return err
+			}
+			charIDs = append(charIDs, charID)
+		}
+		if err := s.removeStale("timeline_characters", e.ID, "event_id", e.ID, "character_id", charIDs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pruneStale deletes seeded rows whose entries were removed from the data
+// files. Tables are pruned before the tables they reference. Rows that users
+// still point at, like owned characters or redeemed codes, make the prune
+// fail rather than taking user data with them.
+func (s *seeder) pruneStale() error {
+	d := s.data
+	ids := func(n int, key func(i int) string) []string {
+		keys := make([]string, n)
+		for i := range keys {
+			keys[i] = key(i)
+		}
+		return keys
+	}
+
+	steps := []struct {
+		file     string
+		table    string
+		column   string
+		keep     []string
+		refs     []pruneRef
+		userRefs []pruneRef
+	}{
+		{"optimal-builds.json", "character_builds", "character_id", slices.Collect(maps.Keys(d.Builds)),
+			[]pruneRef{{"character_build_substats", "build_id"}, {"character_build_sets", "build_id"}}, nil},
+		{"skills.json", "character_skills", "character_id", slices.Collect(maps.Keys(d.Skills)), nil, nil},
+		{"eidolons.json", "eidolons", "character_id", slices.Collect(maps.Keys(d.Eidolons)), nil, nil},
+		{"banners.json", "banners", "slug", ids(len(d.Banners), func(i int) string { return d.Banners[i].ID }),
+			[]pruneRef{{"banner_characters", "banner_id"}}, []pruneRef{{"pull_plans", "banner_id"}}},
+		{"codes.json", "codes", "code", ids(len(d.Codes), func(i int) string { return d.Codes[i].Code }),
+			nil, []pruneRef{{"user_redeemed_codes", "code_id"}}},
+		{"events.json", "events", "slug", ids(len(d.Events), func(i int) string { return d.Events[i].ID }), nil, nil},
+		{"lore/timeline.json", "timeline_events", "id", ids(len(d.Timeline), func(i int) string { return d.Timeline[i].ID }),
+			[]pruneRef{{"timeline_characters", "event_id"}}, nil},
+		{"lore/characters-lore.json", "character_lores", "id", slices.Collect(maps.Keys(d.Lore)),
+			[]pruneRef{{"character_relationships", "from_id"}, {"character_relationships", "to_id"}, {"timeline_characters", "character_id"}}, nil},
+		{"lore/locations.json", "locations", "id", slices.Collect(maps.Keys(d.Locations)),
+			[]pruneRef{{"location_connections", "from_id"}, {"location_connections", "to_id"}}, nil},
+		{"lore/factions.json", "factions", "id", slices.Collect(maps.Keys(d.Factions)), nil, nil},
+		{"light-cones.json", "light_cones", "id", ids(len(d.LightCones), func(i int) string { return d.LightCones[i].ID }),
+			[]pruneRef{{"light_cone_superimpositions", "light_cone_id"}}, []pruneRef{{"user_characters", "light_cone_id"}}},
+		{"enemies.json", "enemies", "id", ids(len(d.Enemies), func(i int) string { return d.Enemies[i].ID }),
+			[]pruneRef{{"enemy_weaknesses", "enemy_id"}, {"enemy_resistances", "enemy_id"}, {"enemy_variants", "enemy_id"}},
+			[]pruneRef{{"battles", "enemy_id"}}},
+		{"characters.json", "characters", "id", ids(len(d.Characters), func(i int) string { return d.Characters[i].ID }),
+			[]pruneRef{{"banner_characters", "character_id"}},
+			[]pruneRef{{"user_characters", "character_id"}, {"team_preset_members", "character_id"}, {"battle_members", "character_id"}}},
+	}
+
+	for _, step := range steps {
+		s.file = step.file
+		if err := s.prune(step.table, step.column, step.keep, step.refs, step.userRefs); err != nil {
+			return err
+		}
+	}
 	return nil
 }
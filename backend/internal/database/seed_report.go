@@ -0,0 +1,274 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"reflect"
+	"sort"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// tableDiff records what a seed run changed in one table
+type tableDiff struct {
+	File      string
+	Table     string
+	Created   []string
+	Updated   []string // Key plus the changed columns
+	Deleted   []string
+	Unchanged int
+}
+
+// seedReport is the diff of a seed run, in the order tables were touched
+type seedReport struct {
+	tables []*tableDiff
+}
+
+func (r *seedReport) table(file, table string) *tableDiff {
+	for _, t := range r.tables {
+		if t.File == file && t.Table == table {
+			return t
+		}
+	}
+	t := &tableDiff{File: file, Table: table}
+	r.tables = append(r.tables, t)
+	return t
+}
+
+// log prints a count line per table. Dry runs also list every changed key,
+// since that's what they're for.
+func (r *seedReport) log(detailed bool) {
+	for _, t := range r.tables {
+		log.Printf("   ✓ %s → %s: %d created, %d updated, %d deleted, %d unchanged",
+			t.File, t.Table, len(t.Created), len(t.Updated), len(t.Deleted), t.Unchanged)
+		if !detailed {
+			continue
+		}
+		for _, key := range t.Created {
+			log.Printf("      + %s", key)
+		}
+		for _, key := range t.Updated {
+			log.Printf("      ~ %s", key)
+		}
+		for _, key := range t.Deleted {
+			log.Printf("      - %s", key)
+		}
+	}
+}
+
+// seeder applies the data files inside one transaction and records the diff
+type seeder struct {
+	tx     *gorm.DB
+	data   *seedData
+	opts   SeedOptions
+	report *seedReport
+	file   string // Data file being applied, for the report
+}
+
+func (s *seeder) diff(table string) *tableDiff {
+	return s.report.table(s.file, table)
+}
+
+// row reads one row as column values, or nil if there's none
+func (s *seeder) row(table, where string, args ...interface{}) (map[string]interface{}, error) {
+	var rows []map[string]interface{}
+	if err := s.tx.Table(table).Where(where, args...).Limit(1).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	return rows[0], nil
+}
+
+// skippedColumns lists the columns to leave out of a full-row write:
+// unset primary keys, which the database generates, and an unset created_at
+func skippedColumns(sch *schema.Schema, value reflect.Value) []string {
+	var skipped []string
+	for _, field := range sch.PrimaryFields {
+		if _, zero := field.ValueOf(context.Background(), value); zero {
+			skipped = append(skipped, field.DBName)
+		}
+	}
+	if field := sch.LookUpField("created_at"); field != nil {
+		if _, zero := field.ValueOf(context.Background(), value); zero {
+			skipped = append(skipped, field.DBName)
+		}
+	}
+	return skipped
+}
+
+// upsert creates or updates record, matched on its natural key, and records
+// which columns changed. Every column is written, so values set back to
+// false or zero in the data files are applied too. The stored row is read
+// back into record, so generated IDs are available to the caller.
+func (s *seeder) upsert(table, key string, record interface{}, where string, args ...interface{}) error {
+	diff := s.diff(table)
+
+	stmt := &gorm.Statement{DB: s.tx}
+	if err := stmt.Parse(record); err != nil {
+		return err
+	}
+	value := reflect.Indirect(reflect.ValueOf(record))
+	skipped := skippedColumns(stmt.Schema, value)
+
+	// Mark the row as coming from the data files, which makes it prunable
+	if field := stmt.Schema.LookUpField("seeded"); field != nil {
+		if err := field.Set(context.Background(), value, true); err != nil {
+			return err
+		}
+	}
+
+	before, err := s.row(table, where, args...)
+	if err != nil {
+		return err
+	}
+
+	if before == nil {
+		// Create swaps zero values for the column defaults, so keep what the
+		// data file says and write it over the new row
+		wanted := reflect.New(value.Type()).Elem()
+		wanted.Set(value)
+		if err := s.tx.Create(record).Error; err != nil {
+			return fmt.Errorf("failed to create %s '%s': %w", table, key, err)
+		}
+		for _, field := range stmt.Schema.PrimaryFields {
+			id, _ := field.ValueOf(context.Background(), value)
+			if err := field.Set(context.Background(), wanted, id); err != nil {
+				return err
+			}
+		}
+		value.Set(wanted)
+		if err := s.tx.Model(record).Select("*").Omit(skipped...).Updates(record).Error; err != nil {
+			return fmt.Errorf("failed to create %s '%s': %w", table, key, err)
+		}
+		diff.Created = append(diff.Created, key)
+		return nil
+	}
+
+	if err := s.tx.Model(record).Where(where, args...).Select("*").Omit(skipped...).Updates(record).Error; err != nil {
+		return fmt.Errorf("failed to update %s '%s': %w", table, key, err)
+	}
+	if err := s.tx.Where(where, args...).Take(record).Error; err != nil {
+		return err
+	}
+
+	after, err := s.row(table, where, args...)
+	if err != nil {
+		return err
+	}
+	if changed := changedColumns(before, after); len(changed) > 0 {
+		diff.Updated = append(diff.Updated, fmt.Sprintf("%s %v", key, changed))
+	} else {
+		diff.Unchanged++
+	}
+	return nil
+}
+
+func changedColumns(before, after map[string]interface{}) []string {
+	var changed []string
+	for column, value := range after {
+		if column == "updated_at" || column == "seeded" {
+			continue
+		}
+		if !reflect.DeepEqual(before[column], value) {
+			changed = append(changed, column)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// removeStale deletes a parent's child rows whose key is no longer listed in
+// the data, e.g. a substat dropped from a build. Unlike pruning, this always
+// runs: child rows are part of their parent's record.
+func (s *seeder) removeStale(table, parentKey, parentColumn string, parentID interface{}, keyColumn string, keep []interface{}) error {
+	query := s.tx.Table(table).Where(parentColumn+" = ?", parentID)
+	if len(keep) > 0 {
+		query = query.Where(keyColumn+" NOT IN ?", keep)
+	}
+
+	var stale []map[string]interface{}
+	if err := query.Select("id", keyColumn).Find(&stale).Error; err != nil {
+		return err
+	}
+	if len(stale) == 0 {
+		return nil
+	}
+
+	ids := make([]interface{}, len(stale))
+	diff := s.diff(table)
+	for i, row := range stale {
+		ids[i] = row["id"]
+		diff.Deleted = append(diff.Deleted, fmt.Sprintf("%s/%v", parentKey, row[keyColumn]))
+	}
+	return s.tx.Exec("DELETE FROM "+table+" WHERE id IN ?", ids).Error
+}
+
+// pruneRef is a column in another table that references the pruned rows' id
+type pruneRef struct {
+	Table  string
+	Column string
+}
+
+// prune deletes seeded rows whose natural key is no longer in the data,
+// along with the game data rows referencing them. Rows seed didn't write,
+// like codes added through the admin API, are kept. Rows still referenced
+// from user data make the prune fail instead.
+func (s *seeder) prune(table, keyColumn string, keep []string, refs, userRefs []pruneRef) error {
+	query := s.tx.Table(table).Where("seeded = ?", true)
+	if len(keep) > 0 {
+		query = query.Where(keyColumn+" NOT IN ?", keep)
+	}
+
+	var stale []map[string]interface{}
+	if err := query.Select("id", keyColumn).Order(keyColumn).Find(&stale).Error; err != nil {
+		return err
+	}
+	if len(stale) == 0 {
+		return nil
+	}
+
+	ids := make([]interface{}, len(stale))
+	for i, row := range stale {
+		ids[i] = row["id"]
+	}
+
+	for _, ref := range userRefs {
+		var count int64
+		if err := s.tx.Table(ref.Table).Where(ref.Column+" IN ?", ids).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return fmt.Errorf("can't prune %s: %d %s rows still reference them", table, count, ref.Table)
+		}
+	}
+
+	for _, ref := range refs {
+		var refIDs []interface{}
+		if err := s.tx.Table(ref.Table).Where(ref.Column+" IN ?", ids).Pluck("id", &refIDs).Error; err != nil {
+			return err
+		}
+		if len(refIDs) == 0 {
+			continue
+		}
+		diff := s.diff(ref.Table)
+		for _, id := range refIDs {
+			diff.Deleted = append(diff.Deleted, fmt.Sprintf("#%v", id))
+		}
+		if err := s.tx.Exec("DELETE FROM "+ref.Table+" WHERE id IN ?", refIDs).Error; err != nil {
+			return fmt.Errorf("failed to prune %s: %w", ref.Table, err)
+		}
+	}
+
+	diff := s.diff(table)
+	for _, row := range stale {
+		diff.Deleted = append(diff.Deleted, fmt.Sprint(row[keyColumn]))
+	}
+	if err := s.tx.Exec("DELETE FROM "+table+" WHERE id IN ?", ids).Error; err != nil {
+		return fmt.Errorf("failed to prune %s: %w", table, err)
+	}
+	return nil
+}
@@ -0,0 +1,407 @@
+package database
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hsr-tools/backend/internal/models"
+)
+
+// seedData holds every data file, parsed and ready to validate
+type seedData struct {
+	Characters []CharacterJSON
+	LightCones []LightConeJSON
+	Skills     map[string]SkillJSON
+	Eidolons   map[string][]EidolonJSON
+	Builds     map[string]BuildJSON
+	Enemies    []EnemyJSON
+	Banners    []BannerJSON
+	Codes      []CodeJSON
+	Events     []EventJSON
+	Factions   map[string]FactionJSON
+	Locations  map[string]LocationJSON
+	Lore       map[string]CharacterLoreJSON
+	Timeline   []TimelineEventJSON
+
+	characterIDs   map[string]bool
+	characterNames map[string]bool
+}
+
+func (d *seedData) hasCharacter(id string) bool {
+	return d.characterIDs[id]
+}
+
+// seedProblems collects validation results across all data files. Errors
+// stop the seed; warnings are entries that will be skipped, such as
+// references to characters the data doesn't have yet.
+type seedProblems struct {
+	errors   []string
+	warnings []string
+}
+
+func (p *seedProblems) errorf(file, format string, args ...interface{}) {
+	p.errors = append(p.errors, file+": "+fmt.Sprintf(format, args...))
+}
+
+func (p *seedProblems) warnf(file, format string, args ...interface{}) {
+	p.warnings = append(p.warnings, file+": "+fmt.Sprintf(format, args...))
+}
+
+func (p *seedProblems) log() {
+	// Map files are validated in random order, so sort for a stable report
+	sort.Strings(p.warnings)
+	sort.Strings(p.errors)
+	for _, w := range p.warnings {
+		log.Printf("   ⚠ %s", w)
+	}
+	for _, e := range p.errors {
+		log.Printf("   ✗ %s", e)
+	}
+}
+
+func (p *seedProblems) err() error {
+	if len(p.errors) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d problems in data files:\n  %s", len(p.errors), strings.Join(p.errors, "\n  "))
+}
+
+// loadSeedData reads and validates every data file. All files are checked
+// before returning, so one run reports every problem at once.
+func loadSeedData(dataPath string) (*seedData, *seedProblems) {
+	d := &seedData{}
+	p := &seedProblems{}
+
+	read := func(name string, v interface{}) {
+		file, err := os.ReadFile(filepath.Join(dataPath, name))
+		if err != nil {
+			p.errorf(name, "failed to read: %v", err)
+			return
+		}
+		if err := json.Unmarshal(file, v); err != nil {
+			var typeErr *json.UnmarshalTypeError
+			if errors.As(err, &typeErr) {
+				p.errorf(name, "field %q should be %s, got %s", typeErr.Field, typeErr.Type, typeErr.Value)
+				return
+			}
+			p.errorf(name, "invalid JSON: %v", err)
+		}
+	}
+
+	read("characters.json", &d.Characters)
+	read("light-cones.json", &d.LightCones)
+	read("skills.json", &d.Skills)
+	read("eidolons.json", &d.Eidolons)
+	read("optimal-builds.json", &d.Builds)
+	read("enemies.json", &d.Enemies)
+	read("banners.json", &d.Banners)
+	read("codes.json", &d.Codes)
+	read("events.json", &d.Events)
+	read("lore/factions.json", &d.Factions)
+	read("lore/locations.json", &d.Locations)
+	read("lore/characters-lore.json", &d.Lore)
+	read("lore/timeline.json", &d.Timeline)
+	if len(p.errors) > 0 {
+		return d, p
+	}
+
+	d.validate(p)
+	return d, p
+}
+
+func (d *seedData) validate(p *seedProblems) {
+	elements := make(map[string]bool, len(defaultElements))
+	for _, e := range defaultElements {
+		elements[e.Name] = true
+	}
+	paths := make(map[string]bool, len(defaultPaths))
+	for _, path := range defaultPaths {
+		paths[path.Name] = true
+	}
+
+	// Duplicate keys aren't errors since the last entry simply wins, but
+	// they're usually a copy-paste mistake worth fixing
+	duplicates := func(file, key string, seen map[string]bool) {
+		if seen[key] {
+			p.warnf(file, "duplicate '%s', the last entry is used", key)
+		}
+		seen[key] = true
+	}
+
+	const charactersFile = "characters.json"
+	d.characterIDs = make(map[string]bool, len(d.Characters))
+	d.characterNames = make(map[string]bool, len(d.Characters))
+	for i, c := range d.Characters {
+		if c.ID == "" {
+			p.errorf(charactersFile, "entry %d has no id", i)
+			continue
+		}
+		duplicates(charactersFile, c.ID, d.characterIDs)
+		d.characterNames[c.Name] = true
+		if c.Name == "" || c.CharID == "" {
+			p.errorf(charactersFile, "'%s' needs a name and charId", c.ID)
+		}
+		if !elements[c.Element] {
+			p.errorf(charactersFile, "unknown element '%s' for '%s'", c.Element, c.ID)
+		}
+		if !paths[c.Path] {
+			p.errorf(charactersFile, "unknown path '%s' for '%s'", c.Path, c.ID)
+		}
+		if c.Rarity != 4 && c.Rarity != 5 {
+			p.errorf(charactersFile, "rarity for '%s' must be 4 or 5, got %d", c.ID, c.Rarity)
+		}
+		if c.BaseSpeed <= 0 {
+			p.errorf(charactersFile, "baseSpeed for '%s' must be positive", c.ID)
+		}
+	}
+
+	const lightConesFile = "light-cones.json"
+	seen := map[string]bool{}
+	for i, lc := range d.LightCones {
+		if lc.ID == "" {
+			p.errorf(lightConesFile, "entry %d has no id", i)
+			continue
+		}
+		duplicates(lightConesFile, lc.ID, seen)
+		if lc.Name == "" || lc.GameID == "" {
+			p.errorf(lightConesFile, "'%s' needs a name and gameId", lc.ID)
+		}
+		if !paths[lc.Path] {
+			p.errorf(lightConesFile, "unknown path '%s' for '%s'", lc.Path, lc.ID)
+		}
+		if lc.Rarity < 3 || lc.Rarity > 5 {
+			p.errorf(lightConesFile, "rarity for '%s' must be 3 to 5, got %d", lc.ID, lc.Rarity)
+		}
+		ranks := map[int]bool{}
+		for _, s := range lc.Superimpositions {
+			if s.Rank < 1 || s.Rank > 5 || ranks[s.Rank] {
+				p.errorf(lightConesFile, "invalid or repeated superimposition rank %d for '%s'", s.Rank, lc.ID)
+			}
+			ranks[s.Rank] = true
+		}
+	}
+
+	for id, s := range d.Skills {
+		if !d.hasCharacter(id) {
+			p.warnf("skills.json", "unknown character '%s', skipped", id)
+		}
+		if s.BasicMultiplier < 0 || s.SkillMultiplier < 0 || s.UltMultiplier < 0 || s.UltCost < 0 {
+			p.errorf("skills.json", "negative multiplier or cost for '%s'", id)
+		}
+	}
+
+	for id, eidolons := range d.Eidolons {
+		if !d.hasCharacter(id) {
+			p.warnf("eidolons.json", "unknown character '%s', skipped", id)
+		}
+		ranks := map[int]bool{}
+		for _, e := range eidolons {
+			if e.Rank < 1 || e.Rank > models.MaxEidolon || ranks[e.Rank] {
+				p.errorf("eidolons.json", "invalid or repeated eidolon rank %d for '%s'", e.Rank, id)
+			}
+			ranks[e.Rank] = true
+			if e.Name == "" {
+				p.errorf("eidolons.json", "eidolon %d for '%s' has no name", e.Rank, id)
+			}
+		}
+	}
+
+	for id, b := range d.Builds {
+		if !d.hasCharacter(id) {
+			p.warnf("optimal-builds.json", "unknown character '%s', skipped", id)
+		}
+		for stat, weight := range b.Substats {
+			if weight < 0 || weight > 1 {
+				p.errorf("optimal-builds.json", "substat weight for %s on '%s' must be between 0 and 1", stat, id)
+			}
+		}
+		for _, set := range b.Sets {
+			if set == "" {
+				p.errorf("optimal-builds.json", "empty relic set name for '%s'", id)
+			}
+		}
+	}
+
+	const enemiesFile = "enemies.json"
+	seen = map[string]bool{}
+	for i, e := range d.Enemies {
+		if e.ID == "" {
+			p.errorf(enemiesFile, "entry %d has no id", i)
+			continue
+		}
+		duplicates(enemiesFile, e.ID, seen)
+		if e.Name == "" || e.Type == "" {
+			p.errorf(enemiesFile, "'%s' needs a name and type", e.ID)
+		}
+		if e.HP <= 0 {
+			p.errorf(enemiesFile, "hp for '%s' must be positive", e.ID)
+		}
+		for _, elem := range e.Weakness {
+			if !elements[elem] {
+				p.errorf(enemiesFile, "unknown weakness '%s' for '%s'", elem, e.ID)
+			}
+		}
+		for elem := range e.Resistance {
+			if !elements[elem] {
+				p.errorf(enemiesFile, "unknown resistance '%s' for '%s'", elem, e.ID)
+			}
+		}
+		variants := map[string]bool{}
+		for _, v := range e.Variants {
+			if v.Name == "" || variants[v.Name] {
+				p.errorf(enemiesFile, "missing or repeated variant name '%s' for '%s'", v.Name, e.ID)
+			}
+			variants[v.Name] = true
+		}
+	}
+
+	const bannersFile = "banners.json"
+	seen = map[string]bool{}
+	for i, b := range d.Banners {
+		if b.ID == "" {
+			p.errorf(bannersFile, "entry %d has no id", i)
+			continue
+		}
+		duplicates(bannersFile, b.ID, seen)
+		if b.Name == "" {
+			p.errorf(bannersFile, "'%s' has no name", b.ID)
+		}
+		if _, _, err := parseDateRange(bannersFile, b.ID, b.StartDate, b.EndDate); err != nil {
+			p.errors = append(p.errors, err.Error())
+		}
+		for _, names := range [][]string{b.Characters, b.FourStars} {
+			for _, name := range names {
+				if !d.characterNames[name] {
+					p.warnf(bannersFile, "unknown character '%s' on '%s', skipped", name, b.ID)
+				}
+			}
+		}
+	}
+
+	const codesFile = "codes.json"
+	seen = map[string]bool{}
+	for i, c := range d.Codes {
+		if c.Code == "" {
+			p.errorf(codesFile, "entry %d has no code", i)
+			continue
+		}
+		// Codes are looked up case-insensitively
+		duplicates(codesFile, strings.ToUpper(c.Code), seen)
+		if c.Rewards == "" {
+			p.errorf(codesFile, "'%s' has no rewards", c.Code)
+		}
+		switch c.Status {
+		case "new", "active", "expired":
+		default:
+			p.errorf(codesFile, "status for '%s' must be new, active or expired, got %q", c.Code, c.Status)
+		}
+		if c.AddedAt != "" {
+			if _, err := parseDate(codesFile, c.Code, "addedAt", c.AddedAt); err != nil {
+				p.errors = append(p.errors, err.Error())
+			}
+		}
+		if c.ExpiresAt != nil {
+			if _, err := parseDate(codesFile, c.Code, "expiresAt", *c.ExpiresAt); err != nil {
+				p.errors = append(p.errors, err.Error())
+			}
+		}
+	}
+
+	const eventsFile = "events.json"
+	seen = map[string]bool{}
+	for i, e := range d.Events {
+		if e.ID == "" {
+			p.errorf(eventsFile, "entry %d has no id", i)
+			continue
+		}
+		duplicates(eventsFile, e.ID, seen)
+		if e.Name == "" {
+			p.errorf(eventsFile, "'%s' has no name", e.ID)
+		}
+		if _, _, err := parseDateRange(eventsFile, e.ID, e.StartDate, e.EndDate); err != nil {
+			p.errors = append(p.errors, err.Error())
+		}
+	}
+
+	d.validateLore(p, duplicates)
+}
+
+// validateLore checks the lore files. Their map keys are the IDs used by
+// every cross reference, so a key that doesn't match its id is an error.
+func (d *seedData) validateLore(p *seedProblems, duplicates func(file, key string, seen map[string]bool)) {
+	const (
+		factionsFile  = "lore/factions.json"
+		locationsFile = "lore/locations.json"
+		loreFile      = "lore/characters-lore.json"
+		timelineFile  = "lore/timeline.json"
+	)
+
+	for key, f := range d.Factions {
+		if f.ID != key {
+			p.errorf(factionsFile, "key '%s' doesn't match id '%s'", key, f.ID)
+		}
+		if f.Name == "" {
+			p.errorf(factionsFile, "'%s' has no name", key)
+		}
+	}
+
+	for key, l := range d.Locations {
+		if l.ID != key {
+			p.errorf(locationsFile, "key '%s' doesn't match id '%s'", key, l.ID)
+		}
+		if l.Name == "" {
+			p.errorf(locationsFile, "'%s' has no name", key)
+		}
+		for _, target := range l.ConnectedTo {
+			if _, ok := d.Locations[target]; !ok {
+				p.warnf(locationsFile, "unknown connected location '%s' for '%s', skipped", target, key)
+			}
+		}
+	}
+
+	for key, c := range d.Lore {
+		if c.ID != key {
+			p.errorf(loreFile, "key '%s' doesn't match id '%s'", key, c.ID)
+		}
+		if c.Name == "" {
+			p.errorf(loreFile, "'%s' has no name", key)
+		}
+		if _, ok := d.Factions[c.Faction]; c.Faction != "" && !ok {
+			p.warnf(loreFile, "unknown faction '%s' for '%s', left empty", c.Faction, key)
+		}
+		for _, r := range c.Relationships {
+			if _, ok := d.Lore[r.Target]; !ok {
+				p.warnf(loreFile, "unknown relationship target '%s' for '%s', skipped", r.Target, key)
+			}
+			if r.Type == "" {
+				p.errorf(loreFile, "relationship '%s' -> '%s' has no type", key, r.Target)
+			}
+		}
+	}
+
+	seen := map[string]bool{}
+	for i, e := range d.Timeline {
+		if e.ID == "" {
+			p.errorf(timelineFile, "entry %d has no id", i)
+			continue
+		}
+		duplicates(timelineFile, e.ID, seen)
+		if e.Title == "" {
+			p.errorf(timelineFile, "'%s' has no title", e.ID)
+		}
+		if _, ok := d.Locations[e.Location]; e.Location != "" && !ok {
+			p.warnf(timelineFile, "unknown location '%s' for '%s', left empty", e.Location, e.ID)
+		}
+		for _, charID := range e.Characters {
+			if _, ok := d.Lore[charID]; !ok {
+				p.warnf(timelineFile, "unknown character '%s' in '%s', skipped", charID, e.ID)
+			}
+		}
+	}
+}
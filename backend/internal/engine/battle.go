@@ -0,0 +1,381 @@
+package engine
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/hsr-tools/backend/internal/models"
+)
+
+// Battle constants
+const (
+	BaseActionGauge    = 10000.0
+	FirstCycleAV       = 150.0
+	CycleAV            = 100.0
+	MaxSkillPoints     = 5
+	StartSkillPoints   = 3
+	UltEnergyRefund    = 5
+	HitEnergy          = 10
+	DefaultMaxCycles   = 30
+	MaxAllowedCycles   = 100
+	MaxActions         = 5000 // Log entries before a run is abandoned as runaway
+	BreakDelayFraction = 0.25
+)
+
+// Ultimate timing types stored in CharacterSkill.UltType
+const (
+	UltTypeNormal  = "normal"  // Fired as soon as energy is full
+	UltTypeCounter = "counter" // Held until right after the enemy acts
+	UltTypeStacks  = "stacks"  // Fired on the owner's own turn
+)
+
+// toughnessDamage per action type when hitting a weakness
+var toughnessDamage = map[ActionType]int{
+	ActionBasic:    30,
+	ActionSkill:    60,
+	ActionUltimate: 90,
+}
+
+// Combatant is a team member entering the simulator
+type Combatant struct {
+	ID       string
+	Name     string
+	Speed    float64
+	Attacker Attacker
+	Skill    models.CharacterSkill
+}
+
+// EnemyConfig describes the enemy being fought
+type EnemyConfig struct {
+	ID         string             `json:"id"`
+	Name       string             `json:"name"`
+	HP         int                `json:"hp" binding:"omitempty,gt=0,lte=1000000000"`
+	Speed      float64            `json:"speed" binding:"omitempty,gt=0,lte=1000"`
+	Def        float64            `json:"def" binding:"omitempty,gte=0,lte=100000"`
+	Level      int                `json:"level" binding:"omitempty,gte=1,lte=100"`
+	Toughness  int                `json:"toughness" binding:"omitempty,gte=0,lte=10000"`
+	Weakness   []string           `json:"weakness"`
+	Resistance map[string]float64 `json:"resistance"`
+}
+
+// BattleConfig is the full input for a simulation run
+type BattleConfig struct {
+	Team      []Combatant
+	Enemy     EnemyConfig
+	Seed      int64
+	MaxCycles int
+}
+
+// BattleLogEntry records a single action in the simulation
+type BattleLogEntry struct {
+	Index       int      `json:"index"`
+	Cycle       int      `json:"cycle"`
+	ActionValue float64  `json:"actionValue"`
+	ActorID     string   `json:"actorId"`
+	Actor       string   `json:"actor"`
+	Action      string   `json:"action"`
+	Damage      int      `json:"damage,omitempty"`
+	IsCrit      bool     `json:"isCrit,omitempty"`
+	Energy      int      `json:"energy"`
+	SkillPoints int      `json:"skillPoints"`
+	EnemyHP     int      `json:"enemyHp"`
+	Toughness   int      `json:"toughness"`
+	Effects     []string `json:"effects,omitempty"`
+}
+
+// BattleResult is the outcome of a simulation run
+type BattleResult struct {
+	Seed        int64            `json:"seed"`
+	Victory     bool             `json:"victory"`
+	TotalDamage int              `json:"totalDamage"`
+	Turns       int              `json:"turns"`
+	Cycles      int              `json:"cycles"`
+	ActionValue float64          `json:"actionValue"`
+	DamageBy    map[string]int   `json:"damageBy"`
+	Log         []BattleLogEntry `json:"log"`
+}
+
+// NewCombatant builds a combatant from seeded character data, using the
+// same gear approximation as the frontend simulator
+func NewCombatant(char models.Character, skill models.CharacterSkill) Combatant {
+	attacker := AttackerFromSkill(skill, char.Element.Name)
+	attacker.BaseAtk += 1500
+	attacker.CritRate += 0.6
+	attacker.CritDmg += 1.5
+	attacker.DmgBonus = 0.46
+
+	return Combatant{
+		ID:       char.ID,
+		Name:     char.Name,
+		Speed:    float64(char.BaseSpeed + 30),
+		Attacker: attacker,
+		Skill:    skill,
+	}
+}
+
+type unitState struct {
+	combatant *Combatant
+	nextAV    float64
+	energy    int
+	ultReady  bool
+}
+
+type battleState struct {
+	cfg         BattleConfig
+	rng         *rand.Rand
+	units       []*unitState
+	enemyNextAV float64
+	enemyHP     int
+	toughness   int
+	isBroken    bool
+	skillPoints int
+	clock       float64
+	result      BattleResult
+}
+
+// Simulate runs a deterministic battle. The same config and seed always
+// produce the same log.
+func Simulate(cfg BattleConfig) (BattleResult, error) {
+	if len(cfg.Team) == 0 || len(cfg.Team) > 4 {
+		return BattleResult{}, fmt.Errorf("team must have between 1 and 4 members")
+	}
+	if cfg.Enemy.HP <= 0 || cfg.Enemy.Speed <= 0 {
+		return BattleResult{}, fmt.Errorf("enemy must have positive hp and speed")
+	}
+	if cfg.MaxCycles <= 0 {
+		cfg.MaxCycles = DefaultMaxCycles
+	}
+	if cfg.MaxCycles > MaxAllowedCycles {
+		cfg.MaxCycles = MaxAllowedCycles
+	}
+
+	s := &battleState{
+		cfg:         cfg,
+		rng:         rand.New(rand.NewSource(cfg.Seed)),
+		enemyNextAV: BaseActionGauge / cfg.Enemy.Speed,
+		enemyHP:     cfg.Enemy.HP,
+		toughness:   cfg.Enemy.Toughness,
+		skillPoints: StartSkillPoints,
+		result: BattleResult{
+			Seed:     cfg.Seed,
+			DamageBy: make(map[string]int),
+		},
+	}
+	for i := range cfg.Team {
+		c := &cfg.Team[i]
+		if c.Speed <= 0 {
+			return BattleResult{}, fmt.Errorf("character %s must have positive speed", c.ID)
+		}
+		s.units = append(s.units, &unitState{combatant: c, nextAV: BaseActionGauge / c.Speed})
+	}
+
+	for s.enemyHP > 0 {
+		if len(s.result.Log) >= MaxActions {
+			return BattleResult{}, fmt.Errorf("battle exceeded %d actions", MaxActions)
+		}
+		actor, enemyTurn := s.nextActor()
+		av := s.enemyNextAV
+		if actor != nil {
+			av = actor.nextAV
+		}
+		if s.cycleAt(av) >= cfg.MaxCycles {
+			break
+		}
+		s.clock = av
+
+		if enemyTurn {
+			s.enemyTurn()
+			s.enemyNextAV += BaseActionGauge / cfg.Enemy.Speed
+			s.fireUltimates(UltTypeNormal, UltTypeCounter)
+			continue
+		}
+
+		if actor.ultReady && ultType(actor.combatant.Skill) == UltTypeStacks {
+			s.useUltimate(actor)
+		}
+		if s.enemyHP > 0 {
+			s.characterTurn(actor)
+		}
+		actor.nextAV += BaseActionGauge / actor.combatant.Speed
+		s.fireUltimates(UltTypeNormal)
+	}
+
+	s.result.Victory = s.enemyHP <= 0
+	s.result.Cycles = s.cycleAt(s.clock) + 1
+	s.result.ActionValue = s.clock
+	return s.result, nil
+}
+
+// cycleAt returns the zero-based cycle an action value falls into. The
+// first cycle lasts 150 AV, every following cycle 100 AV.
+func (s *battleState) cycleAt(av float64) int {
+	if av < FirstCycleAV {
+		return 0
+	}
+	return 1 + int((av-FirstCycleAV)/CycleAV)
+}
+
+// nextActor picks the unit with the lowest action value. Ties go to the
+// team in slot order, then the enemy.
+func (s *battleState) nextActor() (*unitState, bool) {
+	var next *unitState
+	for _, u := range s.units {
+		if next == nil || u.nextAV < next.nextAV {
+			next = u
+		}
+	}
+	if s.enemyNextAV < next.nextAV {
+		return nil, true
+	}
+	return next, false
+}
+
+func (s *battleState) characterTurn(u *unitState) {
+	skill := u.combatant.Skill
+	action := ActionBasic
+	if s.skillPoints > 0 {
+		action = ActionSkill
+	}
+
+	effects := []string{}
+	switch action {
+	case ActionSkill:
+		s.skillPoints--
+		s.addEnergy(u, skill.SkillEnergy)
+		effects = append(effects, "-1 SP")
+	default:
+		s.skillPoints = min(MaxSkillPoints, s.skillPoints+1)
+		s.addEnergy(u, skill.BasicEnergy)
+		effects = append(effects, "+1 SP")
+	}
+
+	s.result.Turns++
+	s.attack(u, action, effects)
+}
+
+func (s *battleState) useUltimate(u *unitState) {
+	u.energy = 0
+	u.ultReady = false
+	s.addEnergy(u, UltEnergyRefund)
+	s.attack(u, ActionUltimate, nil)
+}
+
+// fireUltimates lets every unit whose ult type is in types and whose energy
+// is full use its ultimate
+func (s *battleState) fireUltimates(types ...string) {
+	for _, u := range s.units {
+		if s.enemyHP <= 0 {
+			return
+		}
+		if !u.ultReady {
+			continue
+		}
+		for _, t := range types {
+			if ultType(u.combatant.Skill) == t {
+				s.useUltimate(u)
+				break
+			}
+		}
+	}
+}
+
+func (s *battleState) attack(u *unitState, action ActionType, effects []string) {
+	c := u.combatant
+	multiplier := Multiplier(c.Skill, action)
+
+	entry := BattleLogEntry{
+		ActorID: c.ID,
+		Actor:   c.Name,
+		Action:  string(action),
+		Effects: effects,
+	}
+
+	if multiplier > 0 {
+		target := Target{
+			Level:      s.cfg.Enemy.Level,
+			Def:        s.cfg.Enemy.Def,
+			Resistance: s.cfg.Enemy.Resistance,
+			IsBroken:   s.isBroken,
+		}
+		dmg := CalculateDamage(c.Attacker, target, multiplier, nil)
+		damage := dmg.NonCrit
+		if s.rng.Float64() < dmg.Breakdown.CritRate {
+			damage = dmg.Crit
+			entry.IsCrit = true
+		}
+		damage = min(damage, s.enemyHP)
+		s.enemyHP -= damage
+		s.result.TotalDamage += damage
+		s.result.DamageBy[c.ID] += damage
+		entry.Damage = damage
+
+		if !s.isBroken && s.cfg.Enemy.Toughness > 0 && hasWeakness(s.cfg.Enemy.Weakness, c.Attacker.Element) {
+			s.toughness = max(0, s.toughness-toughnessDamage[action])
+			if s.toughness == 0 {
+				s.isBroken = true
+				s.enemyNextAV += BaseActionGauge / s.cfg.Enemy.Speed * BreakDelayFraction
+				entry.Effects = append(entry.Effects, "Weakness Break")
+			}
+		}
+	}
+
+	s.log(u, entry)
+}
+
+func (s *battleState) enemyTurn() {
+	// Recover from break at the start of the enemy's turn
+	effects := []string{}
+	if s.isBroken {
+		s.isBroken = false
+		s.toughness = s.cfg.Enemy.Toughness
+		effects = append(effects, "Recovered from Break")
+	}
+
+	target := s.units[s.rng.Intn(len(s.units))]
+	s.addEnergy(target, HitEnergy)
+	effects = append(effects, fmt.Sprintf("Hit %s (+%d energy)", target.combatant.Name, HitEnergy))
+
+	s.log(nil, BattleLogEntry{
+		ActorID: s.cfg.Enemy.ID,
+		Actor:   s.cfg.Enemy.Name,
+		Action:  "attack",
+		Effects: effects,
+	})
+}
+
+func (s *battleState) addEnergy(u *unitState, amount int) {
+	u.energy = min(u.combatant.Skill.UltCost, u.energy+amount)
+	u.ultReady = u.combatant.Skill.UltCost > 0 && u.energy >= u.combatant.Skill.UltCost
+}
+
+func (s *battleState) log(u *unitState, entry BattleLogEntry) {
+	entry.Index = len(s.result.Log)
+	entry.Cycle = s.cycleAt(s.clock)
+	entry.ActionValue = math.Round(s.clock*100) / 100
+	entry.SkillPoints = s.skillPoints
+	entry.EnemyHP = s.enemyHP
+	entry.Toughness = s.toughness
+	if u != nil {
+		entry.Energy = u.energy
+	}
+	s.result.Log = append(s.result.Log, entry)
+}
+
+func ultType(skill models.CharacterSkill) string {
+	switch skill.UltType {
+	case UltTypeCounter, UltTypeStacks:
+		return skill.UltType
+	default:
+		return UltTypeNormal
+	}
+}
+
+func hasWeakness(weakness []string, element string) bool {
+	for _, w := range weakness {
+		if w == element {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,174 @@
+package engine
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hsr-tools/backend/internal/models"
+)
+
+func testSkill() models.CharacterSkill {
+	return models.CharacterSkill{
+		BasicMultiplier: 1,
+		SkillMultiplier: 2,
+		UltMultiplier:   4,
+		BasicEnergy:     20,
+		SkillEnergy:     30,
+		UltCost:         120,
+		UltType:         UltTypeNormal,
+		BaseAtk:         600,
+		BaseCritRate:    0.05,
+		BaseCritDmg:     0.5,
+	}
+}
+
+func testCombatant(id string, speed float64) Combatant {
+	skill := testSkill()
+	return Combatant{
+		ID:       id,
+		Name:     id,
+		Speed:    speed,
+		Attacker: AttackerFromSkill(skill, "Fire"),
+		Skill:    skill,
+	}
+}
+
+func testEnemy() EnemyConfig {
+	return EnemyConfig{
+		ID:        "dummy",
+		Name:      "Dummy",
+		HP:        50000,
+		Speed:     100,
+		Level:     80,
+		Toughness: 120,
+		Weakness:  []string{"Fire"},
+	}
+}
+
+func TestSimulateRejectsInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  BattleConfig
+		want string
+	}{
+		{
+			name: "empty team",
+			cfg:  BattleConfig{Enemy: testEnemy()},
+			want: "between 1 and 4",
+		},
+		{
+			name: "five members",
+			cfg: BattleConfig{
+				Team:  []Combatant{testCombatant("a", 100), testCombatant("b", 100), testCombatant("c", 100), testCombatant("d", 100), testCombatant("e", 100)},
+				Enemy: testEnemy(),
+			},
+			want: "between 1 and 4",
+		},
+		{
+			name: "zero character speed",
+			cfg:  BattleConfig{Team: []Combatant{testCombatant("a", 0)}, Enemy: testEnemy()},
+			want: "positive speed",
+		},
+		{
+			name: "negative character speed",
+			cfg:  BattleConfig{Team: []Combatant{testCombatant("a", -10)}, Enemy: testEnemy()},
+			want: "positive speed",
+		},
+		{
+			name: "zero enemy speed",
+			cfg: BattleConfig{
+				Team:  []Combatant{testCombatant("a", 100)},
+				Enemy: EnemyConfig{HP: 1000},
+			},
+			want: "positive hp and speed",
+		},
+		{
+			name: "zero enemy hp",
+			cfg: BattleConfig{
+				Team:  []Combatant{testCombatant("a", 100)},
+				Enemy: EnemyConfig{Speed: 100},
+			},
+			want: "positive hp and speed",
+		},
+		{
+			name: "runaway speed",
+			cfg: BattleConfig{
+				Team:  []Combatant{testCombatant("a", 1e9)},
+				Enemy: EnemyConfig{HP: 1 << 30, Speed: 100},
+			},
+			want: "exceeded",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Simulate(tt.cfg)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Simulate() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSimulateIsDeterministic(t *testing.T) {
+	cfg := BattleConfig{
+		Team:  []Combatant{testCombatant("a", 134), testCombatant("b", 101)},
+		Enemy: testEnemy(),
+		Seed:  42,
+	}
+
+	first, err := Simulate(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := Simulate(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Fatal("same seed produced different results")
+	}
+}
+
+func TestSimulateOutcome(t *testing.T) {
+	tests := []struct {
+		name        string
+		hp          int
+		maxCycles   int
+		wantVictory bool
+		maxLog      int
+	}{
+		{name: "one hit kill", hp: 1, wantVictory: true, maxLog: 1},
+		{name: "clears in time", hp: 50000, wantVictory: true, maxLog: MaxActions},
+		{name: "runs out of cycles", hp: 1 << 30, maxCycles: 2, wantVictory: false, maxLog: MaxActions},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enemy := testEnemy()
+			enemy.HP = tt.hp
+			result, err := Simulate(BattleConfig{
+				Team:      []Combatant{testCombatant("a", 120)},
+				Enemy:     enemy,
+				Seed:      7,
+				MaxCycles: tt.maxCycles,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Victory != tt.wantVictory {
+				t.Errorf("Victory = %v, want %v", result.Victory, tt.wantVictory)
+			}
+			if len(result.Log) == 0 || len(result.Log) > tt.maxLog {
+				t.Errorf("log has %d entries, want 1 to %d", len(result.Log), tt.maxLog)
+			}
+			if result.TotalDamage > tt.hp {
+				t.Errorf("TotalDamage = %d, more than the enemy's %d HP", result.TotalDamage, tt.hp)
+			}
+			if tt.maxCycles > 0 && result.Cycles > tt.maxCycles {
+				t.Errorf("Cycles = %d, want at most %d", result.Cycles, tt.maxCycles)
+			}
+		})
+	}
+}
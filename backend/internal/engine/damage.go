@@ -0,0 +1,185 @@
+package engine
+
+import (
+	"math"
+
+	"github.com/hsr-tools/backend/internal/models"
+)
+
+// Default levels used when a request doesn't specify them
+const (
+	DefaultAttackerLevel = 80
+	DefaultEnemyLevel    = 90
+)
+
+// ActionType identifies which skill multiplier an attack uses
+type ActionType string
+
+const (
+	ActionBasic    ActionType = "basic"
+	ActionSkill    ActionType = "skill"
+	ActionUltimate ActionType = "ultimate"
+)
+
+// Buff stats understood by the damage formula
+const (
+	StatAtk           = "atk"
+	StatAtkPercent    = "atkPercent"
+	StatCritRate      = "critRate"
+	StatCritDmg       = "critDmg"
+	StatDmgBonus      = "dmgBonus"
+	StatResPen        = "resPen"
+	StatDefReduction  = "defReduction"
+	StatVulnerability = "vulnerability"
+)
+
+// Buff is a flat or percentage modifier applied to the attack
+type Buff struct {
+	Name   string  `json:"name"`
+	Stat   string  `json:"stat"`
+	Value  float64 `json:"value"`
+	Stacks int     `json:"stacks"`
+}
+
+// Attacker holds the attacking character's stats
+type Attacker struct {
+	Element  string  `json:"element"`
+	BaseAtk  float64 `json:"baseAtk"`
+	CritRate float64 `json:"critRate"`
+	CritDmg  float64 `json:"critDmg"`
+	DmgBonus float64 `json:"dmgBonus"`
+	Level    int     `json:"level"`
+}
+
+// Target holds the defending enemy's stats
+type Target struct {
+	Level      int                `json:"level"`
+	Def        float64            `json:"def"`
+	Resistance map[string]float64 `json:"resistance"`
+	IsBroken   bool               `json:"isBroken"`
+}
+
+// DamageBreakdown exposes every multiplier that went into a hit
+type DamageBreakdown struct {
+	AttackStat              float64 `json:"attackStat"`
+	SkillMultiplier         float64 `json:"skillMultiplier"`
+	CritRate                float64 `json:"critRate"`
+	CritMultiplier          float64 `json:"critMultiplier"`
+	DmgBonusMultiplier      float64 `json:"dmgBonusMultiplier"`
+	DefMultiplier           float64 `json:"defMultiplier"`
+	ResMultiplier           float64 `json:"resMultiplier"`
+	VulnerabilityMultiplier float64 `json:"vulnerabilityMultiplier"`
+	BrokenMultiplier        float64 `json:"brokenMultiplier"`
+}
+
+// DamageResult is the outcome of a single hit
+type DamageResult struct {
+	BaseDamage int             `json:"baseDamage"`
+	NonCrit    int             `json:"nonCrit"`
+	Crit       int             `json:"crit"`
+	Expected   int             `json:"expected"`
+	Breakdown  DamageBreakdown `json:"breakdown"`
+}
+
+// AttackerFromSkill builds an attacker from seeded skill data
+func AttackerFromSkill(skill models.CharacterSkill, element string) Attacker {
+	return Attacker{
+		Element:  element,
+		BaseAtk:  float64(skill.BaseAtk),
+		CritRate: skill.BaseCritRate,
+		CritDmg:  skill.BaseCritDmg,
+		Level:    DefaultAttackerLevel,
+	}
+}
+
+// Multiplier returns the skill multiplier for an action
+func Multiplier(skill models.CharacterSkill, action ActionType) float64 {
+	switch action {
+	case ActionSkill:
+		return skill.SkillMultiplier
+	case ActionUltimate:
+		return skill.UltMultiplier
+	default:
+		return skill.BasicMultiplier
+	}
+}
+
+// CalculateDamage applies the HSR damage formula:
+// ATK × multiplier × crit × DMG bonus × DEF × RES × vulnerability × broken
+func CalculateDamage(attacker Attacker, target Target, multiplier float64, buffs []Buff) DamageResult {
+	attackerLevel := attacker.Level
+	if attackerLevel <= 0 {
+		attackerLevel = DefaultAttackerLevel
+	}
+	targetLevel := target.Level
+	if targetLevel <= 0 {
+		targetLevel = DefaultEnemyLevel
+	}
+
+	// Base damage = ATK * Skill Multiplier
+	attackStat := attacker.BaseAtk*(1+sumStat(buffs, StatAtkPercent)) + sumStat(buffs, StatAtk)
+	baseDamage := attackStat * multiplier
+
+	// Crit
+	critRate := math.Max(0, math.Min(1, attacker.CritRate+sumStat(buffs, StatCritRate)))
+	critMultiplier := 1 + attacker.CritDmg + sumStat(buffs, StatCritDmg)
+
+	// Damage bonus (element DMG%, all DMG%)
+	dmgBonusMultiplier := 1 + attacker.DmgBonus + sumStat(buffs, StatDmgBonus)
+
+	// DEF multiplier
+	defReduction := math.Min(1, sumStat(buffs, StatDefReduction))
+	effectiveDef := target.Def * (1 - defReduction)
+	defMultiplier := float64(attackerLevel+20) / (float64(targetLevel+20) + effectiveDef)
+
+	// Resistance multiplier
+	effectiveRes := math.Max(-1, target.Resistance[attacker.Element]-sumStat(buffs, StatResPen))
+	resMultiplier := 1 - effectiveRes
+
+	// Vulnerability (damage taken increase)
+	vulnerabilityMultiplier := 1 + sumStat(buffs, StatVulnerability)
+
+	// Weakness broken bonus
+	brokenMultiplier := 1.0
+	if target.IsBroken {
+		brokenMultiplier = 1.1
+	}
+
+	nonCrit := baseDamage * dmgBonusMultiplier * defMultiplier * resMultiplier *
+		vulnerabilityMultiplier * brokenMultiplier
+	crit := nonCrit * critMultiplier
+	expected := nonCrit * (1 + critRate*(critMultiplier-1))
+
+	return DamageResult{
+		BaseDamage: int(math.Floor(baseDamage)),
+		NonCrit:    int(math.Floor(nonCrit)),
+		Crit:       int(math.Floor(crit)),
+		Expected:   int(math.Floor(expected)),
+		Breakdown: DamageBreakdown{
+			AttackStat:              attackStat,
+			SkillMultiplier:         multiplier,
+			CritRate:                critRate,
+			CritMultiplier:          critMultiplier,
+			DmgBonusMultiplier:      dmgBonusMultiplier,
+			DefMultiplier:           defMultiplier,
+			ResMultiplier:           resMultiplier,
+			VulnerabilityMultiplier: vulnerabilityMultiplier,
+			BrokenMultiplier:        brokenMultiplier,
+		},
+	}
+}
+
+func sumStat(buffs []Buff, stat string) float64 {
+	total := 0.0
+	for _, b := range buffs {
+		if b.Stat != stat {
+			continue
+		}
+		stacks := b.Stacks
+		if stacks <= 0 {
+			stacks = 1
+		}
+		total += b.Value * float64(stacks)
+	}
+	return total
+}
@@ -0,0 +1,159 @@
+package engine
+
+import (
+	"math"
+	"testing"
+
+	"github.com/hsr-tools/backend/internal/models"
+)
+
+func TestCalculateDamage(t *testing.T) {
+	fire := Attacker{Element: "Fire", BaseAtk: 1000, CritRate: 0.5, CritDmg: 1, Level: 80}
+
+	tests := []struct {
+		name       string
+		attacker   Attacker
+		target     Target
+		multiplier float64
+		buffs      []Buff
+		want       DamageResult
+	}{
+		{
+			name:       "default levels",
+			attacker:   Attacker{Element: "Fire", BaseAtk: 1000, CritRate: 0.5, CritDmg: 1},
+			multiplier: 2,
+			// DEF multiplier is (80+20) / (90+20)
+			want: DamageResult{BaseDamage: 2000, NonCrit: 1818, Crit: 3636, Expected: 2727},
+		},
+		{
+			name:       "stacked buffs",
+			attacker:   Attacker{Element: "Fire", BaseAtk: 1000, CritDmg: 0.5, Level: 80},
+			target:     Target{Level: 80},
+			multiplier: 1,
+			buffs: []Buff{
+				{Stat: StatAtkPercent, Value: 0.25, Stacks: 2},
+				{Stat: StatAtk, Value: 100},
+				{Stat: StatDmgBonus, Value: 0.5},
+			},
+			want: DamageResult{BaseDamage: 1600, NonCrit: 2400, Crit: 3600, Expected: 2400},
+		},
+		{
+			name:       "crit rate capped at 100%",
+			attacker:   fire,
+			target:     Target{Level: 80},
+			multiplier: 1,
+			buffs:      []Buff{{Stat: StatCritRate, Value: 0.75}},
+			want:       DamageResult{BaseDamage: 1000, NonCrit: 1000, Crit: 2000, Expected: 2000},
+		},
+		{
+			name:       "resistance and DEF reduction",
+			attacker:   fire,
+			target:     Target{Level: 80, Def: 100, Resistance: map[string]float64{"Fire": 0.5}},
+			multiplier: 1,
+			buffs: []Buff{
+				{Stat: StatDefReduction, Value: 0.5},
+				{Stat: StatResPen, Value: 0.25},
+			},
+			// 1000 × 100/150 × 0.75
+			want: DamageResult{BaseDamage: 1000, NonCrit: 500, Crit: 1000, Expected: 750},
+		},
+		{
+			name:       "resistance floored at -100%",
+			attacker:   fire,
+			target:     Target{Level: 80},
+			multiplier: 1,
+			buffs:      []Buff{{Stat: StatResPen, Value: 5}},
+			want:       DamageResult{BaseDamage: 1000, NonCrit: 2000, Crit: 4000, Expected: 3000},
+		},
+		{
+			name:       "other element's resistance ignored",
+			attacker:   fire,
+			target:     Target{Level: 80, Resistance: map[string]float64{"Ice": 0.5}},
+			multiplier: 1,
+			want:       DamageResult{BaseDamage: 1000, NonCrit: 1000, Crit: 2000, Expected: 1500},
+		},
+		{
+			name:       "vulnerability on a broken enemy",
+			attacker:   fire,
+			target:     Target{Level: 80, IsBroken: true},
+			multiplier: 1,
+			buffs:      []Buff{{Stat: StatVulnerability, Value: 0.25}},
+			// 1000 × 1.25 × 1.1
+			want: DamageResult{BaseDamage: 1000, NonCrit: 1375, Crit: 2750, Expected: 2062},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CalculateDamage(tt.attacker, tt.target, tt.multiplier, tt.buffs)
+			if got.BaseDamage != tt.want.BaseDamage || got.NonCrit != tt.want.NonCrit ||
+				got.Crit != tt.want.Crit || got.Expected != tt.want.Expected {
+				t.Errorf("CalculateDamage() = base %d, non-crit %d, crit %d, expected %d; want %d, %d, %d, %d",
+					got.BaseDamage, got.NonCrit, got.Crit, got.Expected,
+					tt.want.BaseDamage, tt.want.NonCrit, tt.want.Crit, tt.want.Expected)
+			}
+			if got.Breakdown.CritRate < 0 || got.Breakdown.CritRate > 1 {
+				t.Errorf("Breakdown.CritRate = %v, want between 0 and 1", got.Breakdown.CritRate)
+			}
+		})
+	}
+}
+
+func TestCalculateDamageBreakdown(t *testing.T) {
+	got := CalculateDamage(
+		Attacker{Element: "Ice", BaseAtk: 1000, CritRate: 0.05, CritDmg: 0.5, DmgBonus: 0.25, Level: 80},
+		Target{Level: 80, Def: 100, Resistance: map[string]float64{"Ice": 0.2}, IsBroken: true},
+		2,
+		[]Buff{{Stat: StatCritRate, Value: 0.1, Stacks: 3}, {Stat: StatVulnerability, Value: 0.1}},
+	)
+
+	want := DamageBreakdown{
+		AttackStat:              1000,
+		SkillMultiplier:         2,
+		CritRate:                0.35,
+		CritMultiplier:          1.5,
+		DmgBonusMultiplier:      1.25,
+		DefMultiplier:           0.5,
+		ResMultiplier:           0.8,
+		VulnerabilityMultiplier: 1.1,
+		BrokenMultiplier:        1.1,
+	}
+	fields := []struct {
+		name      string
+		got, want float64
+	}{
+		{"AttackStat", got.Breakdown.AttackStat, want.AttackStat},
+		{"SkillMultiplier", got.Breakdown.SkillMultiplier, want.SkillMultiplier},
+		{"CritRate", got.Breakdown.CritRate, want.CritRate},
+		{"CritMultiplier", got.Breakdown.CritMultiplier, want.CritMultiplier},
+		{"DmgBonusMultiplier", got.Breakdown.DmgBonusMultiplier, want.DmgBonusMultiplier},
+		{"DefMultiplier", got.Breakdown.DefMultiplier, want.DefMultiplier},
+		{"ResMultiplier", got.Breakdown.ResMultiplier, want.ResMultiplier},
+		{"VulnerabilityMultiplier", got.Breakdown.VulnerabilityMultiplier, want.VulnerabilityMultiplier},
+		{"BrokenMultiplier", got.Breakdown.BrokenMultiplier, want.BrokenMultiplier},
+	}
+	for _, f := range fields {
+		if math.Abs(f.got-f.want) > 1e-9 {
+			t.Errorf("Breakdown.%s = %v, want %v", f.name, f.got, f.want)
+		}
+	}
+}
+
+func TestMultiplier(t *testing.T) {
+	skill := models.CharacterSkill{BasicMultiplier: 1, SkillMultiplier: 2.5, UltMultiplier: 4}
+
+	tests := []struct {
+		action ActionType
+		want   float64
+	}{
+		{ActionBasic, 1},
+		{ActionSkill, 2.5},
+		{ActionUltimate, 4},
+		{"unknown", 1},
+	}
+	for _, tt := range tests {
+		if got := Multiplier(skill, tt.action); got != tt.want {
+			t.Errorf("Multiplier(%q) = %v, want %v", tt.action, got, tt.want)
+		}
+	}
+}
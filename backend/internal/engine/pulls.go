@@ -0,0 +1,251 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+)
+
+// Banner types understood by the pull simulator
+const (
+	BannerStandard  = "standard"
+	BannerLightCone = "lightCone"
+)
+
+// Pull simulation limits
+const (
+	DefaultPullIterations = 100000
+	MaxPullIterations     = 2000000
+)
+
+// PityRates describes the 5★ drop rates for a banner type
+type PityRates struct {
+	BaseRate             float64 `json:"baseRate"`
+	SoftPityStart        int     `json:"softPityStart"`
+	HardPity             int     `json:"hardPity"`
+	SoftPityRateIncrease float64 `json:"softPityRateIncrease"`
+	CostPerPull          int     `json:"costPerPull"`
+	GuaranteedPity       bool    `json:"guaranteedPity"`
+	FiftyFiftyRate       float64 `json:"fiftyFiftyRate"`
+}
+
+// pityRates mirrors src/data/pity-rates.json until LoadPityRates is called
+var pityRates = map[string]PityRates{
+	BannerStandard: {
+		BaseRate:             0.006,
+		SoftPityStart:        74,
+		HardPity:             90,
+		SoftPityRateIncrease: 0.06,
+		CostPerPull:          160,
+		GuaranteedPity:       true,
+		FiftyFiftyRate:       0.5,
+	},
+	BannerLightCone: {
+		BaseRate:             0.008,
+		SoftPityStart:        65,
+		HardPity:             80,
+		SoftPityRateIncrease: 0.07,
+		CostPerPull:          160,
+		GuaranteedPity:       true,
+		FiftyFiftyRate:       0.75,
+	},
+}
+
+// LoadPityRates replaces the built-in rates with those in pity-rates.json
+func LoadPityRates(dataPath string) error {
+	file, err := os.ReadFile(filepath.Join(dataPath, "pity-rates.json"))
+	if err != nil {
+		return fmt.Errorf("failed to read pity-rates.json: %w", err)
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(file, &raw); err != nil {
+		return fmt.Errorf("failed to parse pity-rates.json: %w", err)
+	}
+
+	for _, bannerType := range []string{BannerStandard, BannerLightCone} {
+		data, ok := raw[bannerType]
+		if !ok {
+			return fmt.Errorf("pity-rates.json is missing '%s'", bannerType)
+		}
+
+		// Start from the defaults so fields missing in the file (like the
+		// 50/50 rate) keep their built-in value
+		rates := pityRates[bannerType]
+		if err := json.Unmarshal(data, &rates); err != nil {
+			return fmt.Errorf("failed to parse '%s' rates: %w", bannerType, err)
+		}
+		if rates.HardPity <= 0 || rates.BaseRate <= 0 {
+			return fmt.Errorf("invalid '%s' rates in pity-rates.json", bannerType)
+		}
+		pityRates[bannerType] = rates
+	}
+	return nil
+}
+
+// GetPityRates returns the rates for a banner type
+func GetPityRates(bannerType string) (PityRates, bool) {
+	rates, ok := pityRates[bannerType]
+	return rates, ok
+}
+
+// PullProbability returns the chance of a 5★ on the pull that reaches pity
+func PullProbability(pity int, rates PityRates) float64 {
+	if pity < rates.SoftPityStart {
+		return rates.BaseRate
+	}
+
+	// Soft pity increases rate linearly
+	softPityProgress := float64(pity - rates.SoftPityStart + 1)
+	return math.Min(rates.BaseRate+rates.SoftPityRateIncrease*softPityProgress, 1)
+}
+
+// PullSimulation is the input for a Monte Carlo pull simulation
+type PullSimulation struct {
+	BannerType   string
+	CurrentPity  int
+	IsGuaranteed bool
+	Pulls        int
+	Iterations   int
+	Seed         int64
+}
+
+// PullSimulationResult summarizes a Monte Carlo pull simulation
+type PullSimulationResult struct {
+	BannerType   string  `json:"bannerType"`
+	Iterations   int     `json:"iterations"`
+	Seed         int64   `json:"seed"`
+	SuccessRate  float64 `json:"successRate"`
+	AvgPulls     int     `json:"avgPulls"`
+	Distribution []int   `json:"distribution"`
+}
+
+// SimulatePulls estimates the chance of getting the featured 5★ within a
+// pull budget. Distribution[n] counts the runs that succeeded on pull n.
+func SimulatePulls(sim PullSimulation) (PullSimulationResult, error) {
+	rates, ok := GetPityRates(sim.BannerType)
+	if !ok {
+		return PullSimulationResult{}, fmt.Errorf("unknown banner type '%s'", sim.BannerType)
+	}
+	if sim.CurrentPity < 0 || sim.CurrentPity >= rates.HardPity {
+		return PullSimulationResult{}, fmt.Errorf("pity must be between 0 and %d", rates.HardPity-1)
+	}
+	if sim.Pulls < 0 {
+		return PullSimulationResult{}, fmt.Errorf("pulls must not be negative")
+	}
+	if sim.Iterations <= 0 {
+		sim.Iterations = DefaultPullIterations
+	}
+	if sim.Iterations > MaxPullIterations {
+		sim.Iterations = MaxPullIterations
+	}
+
+	rng := rand.New(rand.NewSource(sim.Seed))
+	maxPulls := rates.HardPity * 2
+	distribution := make([]int, maxPulls+1)
+	successes := 0
+	totalPulls := 0
+
+	for i := 0; i < sim.Iterations; i++ {
+		pity := sim.CurrentPity
+		pullCount := 0
+		got5Star := false
+		guaranteed := sim.IsGuaranteed
+
+		for pullCount < sim.Pulls && !got5Star {
+			pity++
+			pullCount++
+
+			if rng.Float64() < PullProbability(pity, rates) || pity >= rates.HardPity {
+				if guaranteed || rng.Float64() < rates.FiftyFiftyRate {
+					got5Star = true
+				} else {
+					// Lost 50/50, reset pity, now guaranteed
+					pity = 0
+					guaranteed = true
+				}
+			}
+		}
+
+		if got5Star {
+			successes++
+			totalPulls += pullCount
+			distribution[min(pullCount, maxPulls)]++
+		}
+	}
+
+	avgPulls := 0
+	if successes > 0 {
+		avgPulls = int(math.Round(float64(totalPulls) / float64(successes)))
+	}
+
+	return PullSimulationResult{
+		BannerType:   sim.BannerType,
+		Iterations:   sim.Iterations,
+		Seed:         sim.Seed,
+		SuccessRate:  float64(successes) / float64(sim.Iterations),
+		AvgPulls:     avgPulls,
+		Distribution: distribution,
+	}, nil
+}
+
+// DailyStellarJadeIncome is the planner's average daily income estimate:
+// dailies (60) + events (~71) + endgame modes (~57)
+const DailyStellarJadeIncome = 188
+
+// ExpectedPulls returns the exact expected number of pulls to obtain the
+// featured 5★ from the given pity and guarantee state
+func ExpectedPulls(bannerType string, currentPity int, isGuaranteed bool) (float64, error) {
+	rates, ok := GetPityRates(bannerType)
+	if !ok {
+		return 0, fmt.Errorf("unknown banner type '%s'", bannerType)
+	}
+	if currentPity < 0 || currentPity >= rates.HardPity {
+		return 0, fmt.Errorf("pity must be between 0 and %d", rates.HardPity-1)
+	}
+
+	expected := expectedPullsToFiveStar(currentPity, rates)
+	if !isGuaranteed {
+		// Losing the 50/50 costs another full run from zero pity
+		expected += (1 - rates.FiftyFiftyRate) * expectedPullsToFiveStar(0, rates)
+	}
+	return expected, nil
+}
+
+// expectedPullsToFiveStar sums the survival probability of every pull up
+// to hard pity
+func expectedPullsToFiveStar(currentPity int, rates PityRates) float64 {
+	expected := 0.0
+	survive := 1.0
+	for pity := currentPity + 1; pity <= rates.HardPity; pity++ {
+		expected += survive
+		if pity >= rates.HardPity {
+			break
+		}
+		survive *= 1 - PullProbability(pity, rates)
+	}
+	return expected
+}
+
+// DaysUntilEnough returns how many days of income are needed before the
+// available jade and passes cover pullsNeeded. It matches daysUntilEnough
+// in src/lib/pullCalculator.ts, which ignores jade short of a full pull.
+func DaysUntilEnough(stellarJade, passes, pullsNeeded, costPerPull, dailyIncome int) int {
+	if costPerPull <= 0 {
+		costPerPull = 160
+	}
+	if dailyIncome <= 0 {
+		dailyIncome = DailyStellarJadeIncome
+	}
+
+	currentPulls := stellarJade/costPerPull + passes
+	if currentPulls >= pullsNeeded {
+		return 0
+	}
+
+	jadeNeeded := (pullsNeeded - currentPulls) * costPerPull
+	return int(math.Ceil(float64(jadeNeeded) / float64(dailyIncome)))
+}
@@ -0,0 +1,257 @@
+package engine
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPullProbability(t *testing.T) {
+	rates, _ := GetPityRates(BannerStandard)
+
+	tests := []struct {
+		name string
+		pity int
+		want float64
+	}{
+		{name: "first pull", pity: 1, want: rates.BaseRate},
+		{name: "before soft pity", pity: rates.SoftPityStart - 1, want: rates.BaseRate},
+		{name: "soft pity start", pity: rates.SoftPityStart, want: rates.BaseRate + rates.SoftPityRateIncrease},
+		{name: "deep soft pity", pity: rates.SoftPityStart + 2, want: rates.BaseRate + 3*rates.SoftPityRateIncrease},
+		{name: "capped at 100%", pity: rates.HardPity, want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PullProbability(tt.pity, rates); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("PullProbability(%d) = %v, want %v", tt.pity, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSimulatePullsRejectsInvalidInput(t *testing.T) {
+	tests := []struct {
+		name string
+		sim  PullSimulation
+		want string
+	}{
+		{name: "unknown banner", sim: PullSimulation{BannerType: "weapon", Pulls: 10}, want: "unknown banner type"},
+		{name: "negative pity", sim: PullSimulation{BannerType: BannerStandard, CurrentPity: -1}, want: "pity must be between"},
+		{name: "pity at hard pity", sim: PullSimulation{BannerType: BannerStandard, CurrentPity: 90}, want: "pity must be between 0 and 89"},
+		{name: "light cone pity", sim: PullSimulation{BannerType: BannerLightCone, CurrentPity: 80}, want: "pity must be between 0 and 79"},
+		{name: "negative pulls", sim: PullSimulation{BannerType: BannerStandard, Pulls: -1}, want: "must not be negative"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := SimulatePulls(tt.sim)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("SimulatePulls() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSimulatePulls(t *testing.T) {
+	tests := []struct {
+		name           string
+		sim            PullSimulation
+		wantIterations int
+		wantRate       float64
+	}{
+		{
+			name:           "no pulls",
+			sim:            PullSimulation{BannerType: BannerStandard, Iterations: 1000},
+			wantIterations: 1000,
+			wantRate:       0,
+		},
+		{
+			name:           "guaranteed at hard pity",
+			sim:            PullSimulation{BannerType: BannerStandard, CurrentPity: 89, IsGuaranteed: true, Pulls: 1, Iterations: 1000},
+			wantIterations: 1000,
+			wantRate:       1,
+		},
+		{
+			name:           "two full pity cycles",
+			sim:            PullSimulation{BannerType: BannerLightCone, Pulls: 160, Iterations: 1000},
+			wantIterations: 1000,
+			wantRate:       1,
+		},
+		{
+			name:           "default iterations",
+			sim:            PullSimulation{BannerType: BannerStandard, CurrentPity: 89, IsGuaranteed: true, Pulls: 1},
+			wantIterations: DefaultPullIterations,
+			wantRate:       1,
+		},
+		{
+			name:           "iterations capped",
+			sim:            PullSimulation{BannerType: BannerStandard, Iterations: MaxPullIterations + 1},
+			wantIterations: MaxPullIterations,
+			wantRate:       0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := SimulatePulls(tt.sim)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Iterations != tt.wantIterations {
+				t.Errorf("Iterations = %d, want %d", result.Iterations, tt.wantIterations)
+			}
+			if result.SuccessRate != tt.wantRate {
+				t.Errorf("SuccessRate = %v, want %v", result.SuccessRate, tt.wantRate)
+			}
+
+			successes := 0
+			for _, n := range result.Distribution {
+				successes += n
+			}
+			if want := int(tt.wantRate * float64(tt.wantIterations)); successes != want {
+				t.Errorf("Distribution counts %d successes, want %d", successes, want)
+			}
+		})
+	}
+}
+
+func TestSimulatePullsIsDeterministic(t *testing.T) {
+	sim := PullSimulation{BannerType: BannerStandard, CurrentPity: 20, Pulls: 120, Iterations: 5000, Seed: 42}
+
+	first, err := SimulatePulls(sim)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := SimulatePulls(sim)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Fatal("same seed produced different results")
+	}
+	if first.SuccessRate <= 0 || first.SuccessRate >= 1 {
+		t.Errorf("SuccessRate = %v, want between 0 and 1", first.SuccessRate)
+	}
+}
+
+func TestExpectedPulls(t *testing.T) {
+	standard, _ := GetPityRates(BannerStandard)
+	fromZero, err := ExpectedPulls(BannerStandard, 0, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		bannerType string
+		pity       int
+		guaranteed bool
+		want       float64
+	}{
+		{name: "last pull before hard pity", bannerType: BannerStandard, pity: 89, guaranteed: true, want: 1},
+		{name: "50/50 adds half a run", bannerType: BannerStandard, pity: 89, want: 1 + (1-standard.FiftyFiftyRate)*fromZero},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExpectedPulls(tt.bannerType, tt.pity, tt.guaranteed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("ExpectedPulls() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// Soft pity puts the average well before hard pity
+	if fromZero < float64(standard.SoftPityStart)/2 || fromZero > float64(standard.HardPity) {
+		t.Errorf("ExpectedPulls(0) = %v, want between %d and %d", fromZero, standard.SoftPityStart/2, standard.HardPity)
+	}
+
+	if _, err := ExpectedPulls("weapon", 0, true); err == nil {
+		t.Error("ExpectedPulls() accepted an unknown banner type")
+	}
+	if _, err := ExpectedPulls(BannerStandard, 90, true); err == nil {
+		t.Error("ExpectedPulls() accepted pity at hard pity")
+	}
+}
+
+func TestDaysUntilEnough(t *testing.T) {
+	tests := []struct {
+		name        string
+		jade        int
+		passes      int
+		pullsNeeded int
+		costPerPull int
+		dailyIncome int
+		want        int
+	}{
+		{name: "already enough", jade: 1600, passes: 0, pullsNeeded: 10, want: 0},
+		{name: "passes count", jade: 0, passes: 10, pullsNeeded: 10, want: 0},
+		{name: "one day short", jade: 1600 - 100, pullsNeeded: 10, dailyIncome: 160, want: 1},
+		// Same as the frontend's daysUntilEnough: leftover jade doesn't count
+		{name: "leftover jade ignored", jade: 159, pullsNeeded: 1, costPerPull: 160, dailyIncome: 1, want: 160},
+		{name: "frontend example", jade: 3300, passes: 5, pullsNeeded: 90, dailyIncome: 188, want: 56},
+		{name: "default income", jade: 0, pullsNeeded: 90, want: 77},
+		{name: "default cost", jade: 0, pullsNeeded: 1, costPerPull: 0, dailyIncome: 100, want: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DaysUntilEnough(tt.jade, tt.passes, tt.pullsNeeded, tt.costPerPull, tt.dailyIncome)
+			if got != tt.want {
+				t.Errorf("DaysUntilEnough() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadPityRates(t *testing.T) {
+	saved := pityRates
+	t.Cleanup(func() { pityRates = saved })
+
+	tests := []struct {
+		name    string
+		file    string
+		wantErr string
+	}{
+		{
+			name: "keeps defaults for missing fields",
+			file: `{"standard":{"baseRate":0.01,"softPityStart":70,"hardPity":90,"softPityRateIncrease":0.05,"costPerPull":160},
+				"lightCone":{"baseRate":0.008,"softPityStart":65,"hardPity":80,"softPityRateIncrease":0.07,"costPerPull":160}}`,
+		},
+		{name: "missing banner type", file: `{"standard":{"baseRate":0.01,"hardPity":90}}`, wantErr: "missing 'lightCone'"},
+		{name: "zero hard pity", file: `{"standard":{"baseRate":0.01,"hardPity":0},"lightCone":{"baseRate":0.01,"hardPity":80}}`, wantErr: "invalid 'standard' rates"},
+		{name: "not JSON", file: `rates`, wantErr: "failed to parse"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pityRates = map[string]PityRates{
+				BannerStandard:  saved[BannerStandard],
+				BannerLightCone: saved[BannerLightCone],
+			}
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, "pity-rates.json"), []byte(tt.file), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			err := LoadPityRates(dir)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LoadPityRates() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			rates, _ := GetPityRates(BannerStandard)
+			if rates.BaseRate != 0.01 || rates.SoftPityStart != 70 {
+				t.Errorf("standard rates = %+v, want the file's values", rates)
+			}
+			if rates.FiftyFiftyRate != saved[BannerStandard].FiftyFiftyRate {
+				t.Errorf("FiftyFiftyRate = %v, want the default %v", rates.FiftyFiftyRate, saved[BannerStandard].FiftyFiftyRate)
+			}
+		})
+	}
+}
@@ -0,0 +1,244 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hsr-tools/backend/internal/models"
+)
+
+// SubstatRoll is the value range of a single substat roll
+type SubstatRoll struct {
+	MaxRoll float64 `json:"maxRoll"`
+	MinRoll float64 `json:"minRoll"`
+}
+
+// substatValues mirrors src/data/substat-values.json until
+// LoadSubstatValues is called
+var substatValues = map[string]SubstatRoll{
+	"CRIT Rate":       {MaxRoll: 3.24, MinRoll: 2.43},
+	"CRIT DMG":        {MaxRoll: 6.48, MinRoll: 4.86},
+	"ATK":             {MaxRoll: 21, MinRoll: 16},
+	"ATK%":            {MaxRoll: 4.32, MinRoll: 3.24},
+	"DEF":             {MaxRoll: 21, MinRoll: 16},
+	"DEF%":            {MaxRoll: 5.4, MinRoll: 4.05},
+	"HP":              {MaxRoll: 42, MinRoll: 31},
+	"HP%":             {MaxRoll: 4.32, MinRoll: 3.24},
+	"SPD":             {MaxRoll: 2.6, MinRoll: 2.0},
+	"Effect Hit Rate": {MaxRoll: 4.32, MinRoll: 3.24},
+	"Effect RES":      {MaxRoll: 4.32, MinRoll: 3.24},
+	"Break Effect":    {MaxRoll: 6.48, MinRoll: 4.86},
+}
+
+// LoadSubstatValues replaces the built-in roll values with those in
+// substat-values.json
+func LoadSubstatValues(dataPath string) error {
+	file, err := os.ReadFile(filepath.Join(dataPath, "substat-values.json"))
+	if err != nil {
+		return fmt.Errorf("failed to read substat-values.json: %w", err)
+	}
+
+	var values map[string]SubstatRoll
+	if err := json.Unmarshal(file, &values); err != nil {
+		return fmt.Errorf("failed to parse substat-values.json: %w", err)
+	}
+	for stat, roll := range values {
+		if roll.MaxRoll <= 0 {
+			return fmt.Errorf("invalid maxRoll for '%s' in substat-values.json", stat)
+		}
+	}
+
+	substatValues = values
+	return nil
+}
+
+// Relic slots with a fixed main stat
+var fixedMainSlots = map[string]bool{"head": true, "hands": true}
+
+// BuildWeights are the substat weights and recommended main stats a relic
+// is graded against
+type BuildWeights struct {
+	Substats  map[string]float64 `json:"substats"`
+	MainStats map[string]string  `json:"mainStats"`
+}
+
+// DefaultBuildWeights matches the "default" entry in optimal-builds.json
+// and is used for characters without a seeded build
+func DefaultBuildWeights() BuildWeights {
+	return BuildWeights{
+		Substats: map[string]float64{
+			"CRIT Rate":       1.0,
+			"CRIT DMG":        1.0,
+			"ATK%":            0.8,
+			"SPD":             0.6,
+			"HP%":             0.4,
+			"DEF%":            0.3,
+			"Effect Hit Rate": 0.3,
+			"Effect RES":      0.3,
+			"Break Effect":    0.3,
+		},
+		MainStats: map[string]string{
+			"body": "CRIT Rate",
+			"feet": "SPD",
+			"orb":  "Element DMG",
+			"rope": "ATK%",
+		},
+	}
+}
+
+// WeightsFromBuild converts a seeded build into scoring weights
+func WeightsFromBuild(build models.CharacterBuild) BuildWeights {
+	weights := BuildWeights{
+		Substats: make(map[string]float64, len(build.Substats)),
+		MainStats: map[string]string{
+			"body": build.BodyMain,
+			"feet": build.FeetMain,
+			"orb":  build.OrbMain,
+			"rope": build.RopeMain,
+		},
+	}
+	for _, s := range build.Substats {
+		weights.Substats[s.StatName] = s.Weight
+	}
+	return weights
+}
+
+// RelicSubstat is a substat on a relic being scored
+type RelicSubstat struct {
+	Type  string  `json:"type" binding:"required"`
+	Value float64 `json:"value"`
+	Rolls int     `json:"rolls"`
+}
+
+// RelicInput is a relic being scored
+type RelicInput struct {
+	Slot     string         `json:"slot" binding:"required,oneof=head hands body feet orb rope"`
+	MainStat string         `json:"mainStat"`
+	Substats []RelicSubstat `json:"substats" binding:"max=4,dive"`
+}
+
+// SubstatScore is one substat's share of a relic score
+type SubstatScore struct {
+	Type         string  `json:"type"`
+	Value        float64 `json:"value"`
+	Rolls        int     `json:"rolls"`
+	MaxValue     float64 `json:"maxValue"`
+	Efficiency   float64 `json:"efficiency"`
+	Weight       float64 `json:"weight"`
+	Contribution float64 `json:"contribution"`
+	IsUseful     bool    `json:"isUseful"`
+}
+
+// RelicScore is the graded result for a relic
+type RelicScore struct {
+	Score         int            `json:"score"`
+	Efficiency    int            `json:"efficiency"`
+	Grade         string         `json:"grade"`
+	MainStatMatch *bool          `json:"mainStatMatch,omitempty"`
+	Substats      []SubstatScore `json:"substats"`
+}
+
+// ScoreRelic grades a relic the same way the frontend rater does: each
+// substat's roll efficiency is weighted by the build, then scaled by how
+// many useful substats the relic has. A main stat that doesn't match the
+// build's recommendation halves the score.
+func ScoreRelic(relic RelicInput, weights BuildWeights) RelicScore {
+	totalWeighted := 0.0
+	totalMax := 0.0
+	usefulStats := 0
+
+	substats := make([]SubstatScore, len(relic.Substats))
+	for i, sub := range relic.Substats {
+		rolls := sub.Rolls
+		if rolls <= 0 {
+			rolls = 1
+		}
+		weight := weights.Substats[sub.Type]
+
+		score := SubstatScore{
+			Type:     sub.Type,
+			Value:    sub.Value,
+			Rolls:    rolls,
+			Weight:   weight,
+			IsUseful: weight >= 0.5,
+		}
+		if roll, ok := substatValues[sub.Type]; ok {
+			score.MaxValue = roll.MaxRoll * float64(rolls)
+			score.Efficiency = sub.Value / score.MaxValue * 100
+		}
+
+		totalWeighted += score.Efficiency / 100 * weight * float64(rolls)
+		totalMax += weight * float64(rolls)
+		if score.IsUseful {
+			usefulStats++
+		}
+		substats[i] = score
+	}
+
+	efficiency := 0.0
+	if totalMax > 0 {
+		efficiency = totalWeighted / totalMax * 100
+		for i := range substats {
+			w := substats[i].Efficiency / 100 * substats[i].Weight * float64(substats[i].Rolls)
+			substats[i].Contribution = round2(w / totalMax * 100)
+		}
+	}
+	for i := range substats {
+		substats[i].Efficiency = round2(substats[i].Efficiency)
+	}
+
+	adjusted := math.Round(math.Round(efficiency) * (0.5 + float64(usefulStats)*0.125))
+
+	result := RelicScore{
+		Efficiency: int(math.Round(efficiency)),
+		Substats:   substats,
+	}
+
+	if recommended := weights.MainStats[relic.Slot]; !fixedMainSlots[relic.Slot] && recommended != "" && relic.MainStat != "" {
+		match := mainStatMatches(relic.MainStat, recommended)
+		result.MainStatMatch = &match
+		if !match {
+			adjusted = math.Round(adjusted * 0.5)
+		}
+	}
+
+	result.Score = int(adjusted)
+	result.Grade = Grade(result.Score)
+	return result
+}
+
+// Grade converts a 0-100 score into a letter grade
+func Grade(score int) string {
+	switch {
+	case score >= 90:
+		return "S"
+	case score >= 80:
+		return "A"
+	case score >= 70:
+		return "B"
+	case score >= 60:
+		return "C"
+	case score >= 50:
+		return "D"
+	default:
+		return "F"
+	}
+}
+
+// mainStatMatches compares a relic's main stat with a build recommendation.
+// "Element DMG" accepts any elemental DMG boost, and upstream names such as
+// "Wind DMG Boost" or "Energy Regeneration Rate" match by prefix.
+func mainStatMatches(mainStat, recommended string) bool {
+	if recommended == "Element DMG" {
+		return strings.Contains(mainStat, "DMG") && !strings.HasPrefix(mainStat, "CRIT")
+	}
+	return strings.HasPrefix(mainStat, recommended)
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,205 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/hsr-tools/backend/internal/models"
+)
+
+// maxRolls is one max roll of each of the default build's top substats
+var maxRolls = []RelicSubstat{
+	{Type: "CRIT Rate", Value: 3.24},
+	{Type: "CRIT DMG", Value: 6.48},
+	{Type: "ATK%", Value: 4.32},
+	{Type: "SPD", Value: 2.6},
+}
+
+func TestScoreRelic(t *testing.T) {
+	tests := []struct {
+		name           string
+		relic          RelicInput
+		wantScore      int
+		wantEfficiency int
+		wantGrade      string
+		wantMatch      *bool
+	}{
+		{
+			name:           "perfect rolls",
+			relic:          RelicInput{Slot: "body", MainStat: "CRIT Rate", Substats: maxRolls},
+			wantScore:      100,
+			wantEfficiency: 100,
+			wantGrade:      "S",
+			wantMatch:      ptr(true),
+		},
+		{
+			name:           "wrong main stat",
+			relic:          RelicInput{Slot: "body", MainStat: "HP%", Substats: maxRolls},
+			wantScore:      50,
+			wantEfficiency: 100,
+			wantGrade:      "D",
+			wantMatch:      ptr(false),
+		},
+		{
+			name:           "fixed main stat slot",
+			relic:          RelicInput{Slot: "head", MainStat: "HP", Substats: maxRolls},
+			wantScore:      100,
+			wantEfficiency: 100,
+			wantGrade:      "S",
+		},
+		{
+			name:           "no main stat given",
+			relic:          RelicInput{Slot: "feet", Substats: maxRolls},
+			wantScore:      100,
+			wantEfficiency: 100,
+			wantGrade:      "S",
+		},
+		{
+			name: "two useful substats",
+			relic: RelicInput{Slot: "hands", Substats: []RelicSubstat{
+				{Type: "CRIT Rate", Value: 3.24},
+				{Type: "CRIT DMG", Value: 6.48},
+				{Type: "HP", Value: 42},
+				{Type: "DEF", Value: 21},
+			}},
+			wantScore:      75,
+			wantEfficiency: 100,
+			wantGrade:      "B",
+		},
+		{
+			name: "half efficiency over two rolls",
+			relic: RelicInput{Slot: "hands", Substats: []RelicSubstat{
+				{Type: "CRIT Rate", Value: 3.24, Rolls: 2},
+			}},
+			wantScore:      31,
+			wantEfficiency: 50,
+			wantGrade:      "F",
+		},
+		{
+			name: "only unweighted substats",
+			relic: RelicInput{Slot: "head", Substats: []RelicSubstat{
+				{Type: "HP", Value: 42},
+				{Type: "Unknown", Value: 10},
+			}},
+			wantScore:      0,
+			wantEfficiency: 0,
+			wantGrade:      "F",
+		},
+		{
+			name:      "no substats",
+			relic:     RelicInput{Slot: "orb", MainStat: "Wind DMG Boost"},
+			wantGrade: "F",
+			wantMatch: ptr(true),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ScoreRelic(tt.relic, DefaultBuildWeights())
+			if got.Score != tt.wantScore || got.Efficiency != tt.wantEfficiency || got.Grade != tt.wantGrade {
+				t.Errorf("ScoreRelic() = score %d, efficiency %d, grade %s; want %d, %d, %s",
+					got.Score, got.Efficiency, got.Grade, tt.wantScore, tt.wantEfficiency, tt.wantGrade)
+			}
+			switch {
+			case tt.wantMatch == nil && got.MainStatMatch != nil:
+				t.Errorf("MainStatMatch = %v, want unset", *got.MainStatMatch)
+			case tt.wantMatch != nil && (got.MainStatMatch == nil || *got.MainStatMatch != *tt.wantMatch):
+				t.Errorf("MainStatMatch = %v, want %v", got.MainStatMatch, *tt.wantMatch)
+			}
+			if len(got.Substats) != len(tt.relic.Substats) {
+				t.Errorf("got %d substat scores, want %d", len(got.Substats), len(tt.relic.Substats))
+			}
+		})
+	}
+}
+
+func TestScoreRelicSubstats(t *testing.T) {
+	got := ScoreRelic(RelicInput{Slot: "head", Substats: []RelicSubstat{
+		{Type: "CRIT Rate", Value: 3.24, Rolls: 0},
+		{Type: "CRIT DMG", Value: 4.86},
+		{Type: "HP", Value: 42},
+	}}, DefaultBuildWeights())
+
+	want := []SubstatScore{
+		{Type: "CRIT Rate", Value: 3.24, Rolls: 1, MaxValue: 3.24, Efficiency: 100, Weight: 1, Contribution: 50, IsUseful: true},
+		{Type: "CRIT DMG", Value: 4.86, Rolls: 1, MaxValue: 6.48, Efficiency: 75, Weight: 1, Contribution: 37.5, IsUseful: true},
+		{Type: "HP", Value: 42, Rolls: 1, MaxValue: 42, Efficiency: 100},
+	}
+	for i := range want {
+		if got.Substats[i] != want[i] {
+			t.Errorf("Substats[%d] = %+v, want %+v", i, got.Substats[i], want[i])
+		}
+	}
+}
+
+func TestMainStatMatches(t *testing.T) {
+	tests := []struct {
+		mainStat    string
+		recommended string
+		want        bool
+	}{
+		{"CRIT Rate", "CRIT Rate", true},
+		{"CRIT DMG", "CRIT Rate", false},
+		{"Wind DMG Boost", "Element DMG", true},
+		{"Physical DMG Boost", "Element DMG", true},
+		{"CRIT DMG", "Element DMG", false},
+		{"ATK%", "Element DMG", false},
+		{"Energy Regeneration Rate", "Energy Regeneration", true},
+		{"ATK", "ATK%", false},
+	}
+	for _, tt := range tests {
+		if got := mainStatMatches(tt.mainStat, tt.recommended); got != tt.want {
+			t.Errorf("mainStatMatches(%q, %q) = %v, want %v", tt.mainStat, tt.recommended, got, tt.want)
+		}
+	}
+}
+
+func TestGrade(t *testing.T) {
+	tests := []struct {
+		score int
+		want  string
+	}{
+		{100, "S"},
+		{90, "S"},
+		{89, "A"},
+		{80, "A"},
+		{70, "B"},
+		{60, "C"},
+		{50, "D"},
+		{49, "F"},
+		{0, "F"},
+	}
+	for _, tt := range tests {
+		if got := Grade(tt.score); got != tt.want {
+			t.Errorf("Grade(%d) = %q, want %q", tt.score, got, tt.want)
+		}
+	}
+}
+
+func TestWeightsFromBuild(t *testing.T) {
+	weights := WeightsFromBuild(models.CharacterBuild{
+		BodyMain: "CRIT DMG",
+		FeetMain: "ATK%",
+		OrbMain:  "Quantum DMG Boost",
+		RopeMain: "ATK%",
+		Substats: []models.CharacterBuildSubstat{
+			{StatName: "CRIT DMG", Weight: 1},
+			{StatName: "SPD", Weight: 0.5},
+		},
+	})
+
+	if len(weights.Substats) != 2 || weights.Substats["CRIT DMG"] != 1 || weights.Substats["SPD"] != 0.5 {
+		t.Errorf("Substats = %v, want CRIT DMG 1 and SPD 0.5", weights.Substats)
+	}
+	if weights.MainStats["body"] != "CRIT DMG" || weights.MainStats["orb"] != "Quantum DMG Boost" {
+		t.Errorf("MainStats = %v, want the build's main stats", weights.MainStats)
+	}
+
+	got := ScoreRelic(RelicInput{Slot: "body", MainStat: "CRIT Rate"}, weights)
+	if got.MainStatMatch == nil || *got.MainStatMatch {
+		t.Errorf("MainStatMatch = %v, want false against the build's CRIT DMG body", got.MainStatMatch)
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/hsr-tools/backend/internal/database"
+	"github.com/hsr-tools/backend/internal/models"
+)
+
+type ActivityPage struct {
+	Data     []models.Activity `json:"data"`
+	Page     int               `json:"page"`
+	PageSize int               `json:"pageSize"`
+	Total    int64             `json:"total"`
+}
+
+// recordActivity appends an entry to the user's feed. Failures are logged
+// and never fail the request that triggered them.
+func recordActivity(userID uuid.UUID, activityType string, data interface{}) {
+	activity := models.Activity{
+		UserID: userID,
+		Type:   activityType,
+	}
+
+	if data != nil {
+		raw, err := json.Marshal(data)
+		if err != nil {
+			log.Printf("Warning: failed to encode %s activity: %v", activityType, err)
+			return
+		}
+		activity.Data = raw
+	}
+
+	if err := database.DB.Create(&activity).Error; err != nil {
+		log.Printf("Warning: failed to record %s activity: %v", activityType, err)
+	}
+}
+
+func GetUserActivity(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "page must be a positive integer"})
+		return
+	}
+	pageSize, err := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
+	if err != nil || pageSize < 1 || pageSize > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "pageSize must be between 1 and 100"})
+		return
+	}
+
+	query := database.DB.Model(&models.Activity{}).Where("user_id = ?", userID)
+	if activityType := c.Query("type"); activityType != "" {
+		query = query.Where("type = ?", activityType)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch activity"})
+		return
+	}
+
+	activities := []models.Activity{}
+	if err := query.Order("created_at DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&activities).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch activity"})
+		return
+	}
+
+	c.JSON(http.StatusOK, ActivityPage{
+		Data:     activities,
+		Page:     page,
+		PageSize: pageSize,
+		Total:    total,
+	})
+}
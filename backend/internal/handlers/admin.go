@@ -0,0 +1,643 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"reflect"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+	"github.com/hsr-tools/backend/internal/database"
+	"github.com/hsr-tools/backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// adminHooks customise the generic admin handlers for one entity
+type adminHooks struct {
+	Validate     func() error                            // Runs after the body is bound
+	Changed      func(fields map[string]json.RawMessage) // Runs on update with the fields the body set
+	AfterSave    func(tx *gorm.DB) error                 // Saves child rows
+	BeforeDelete func(tx *gorm.DB) error                 // Removes child rows
+}
+
+type AuditLogPage struct {
+	Data     []models.AuditLog `json:"data"`
+	Page     int               `json:"page"`
+	PageSize int               `json:"pageSize"`
+	Total    int64             `json:"total"`
+}
+
+// recordAudit writes an audit entry inside the change's transaction, so a
+// change is never committed without its audit row
+func recordAudit(tx *gorm.DB, c *gin.Context, action, entity string, before, after interface{}) error {
+	userID, _ := c.Get("userID")
+	entry := models.AuditLog{
+		UserID: userID.(uuid.UUID),
+		Action: action,
+		Entity: entity,
+		IP:     c.ClientIP(),
+	}
+
+	for _, snapshot := range []struct {
+		value interface{}
+		dest  *json.RawMessage
+	}{{before, &entry.Before}, {after, &entry.After}} {
+		if snapshot.value == nil {
+			continue
+		}
+		raw, err := json.Marshal(snapshot.value)
+		if err != nil {
+			return err
+		}
+		*snapshot.dest = raw
+		var ident struct {
+			ID json.RawMessage `json:"id"`
+		}
+		if json.Unmarshal(raw, &ident) == nil && entry.EntityID == "" {
+			var id interface{}
+			json.Unmarshal(ident.ID, &id)
+			entry.EntityID = fmt.Sprint(id)
+		}
+	}
+
+	return tx.Create(&entry).Error
+}
+
+// adminConflict is a hook error whose text is safe to show the client
+type adminConflict string
+
+func (e adminConflict) Error() string { return string(e) }
+
+// adminError answers a failed save or delete. Database errors are logged
+// rather than returned, since they carry SQL and constraint details.
+func adminError(c *gin.Context, action, entity string, err error) {
+	var conflict adminConflict
+	switch {
+	case errors.As(err, &conflict):
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Can't %s %s: %s", action, entity, conflict)})
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("A %s with the same key already exists", entity)})
+	case errors.Is(err, gorm.ErrForeignKeyViolated) && action == "delete":
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("The %s is still referenced elsewhere", entity)})
+	case errors.Is(err, gorm.ErrForeignKeyViolated):
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("The %s references a record that doesn't exist", entity)})
+	default:
+		log.Printf("Warning: failed to %s %s: %v", action, entity, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to %s %s", action, entity)})
+	}
+}
+
+// adminCreate binds a new record from the body and saves it with an audit
+// entry. record must be a pointer to a zero model.
+func adminCreate(c *gin.Context, entity string, record interface{}, hooks adminHooks) {
+	if err := c.ShouldBindJSON(record); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if hooks.Validate != nil {
+		if err := hooks.Validate(); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(record).Error; err != nil {
+			return err
+		}
+		if hooks.AfterSave != nil {
+			if err := hooks.AfterSave(tx); err != nil {
+				return err
+			}
+		}
+		return recordAudit(tx, c, models.AuditCreate, entity, nil, record)
+	})
+	if err != nil {
+		adminError(c, "create", entity, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, record)
+}
+
+// adminUpdate applies the body as a partial update over the stored record.
+// Fields missing from the body keep their current values.
+func adminUpdate(c *gin.Context, entity string, record interface{}, hooks adminHooks) {
+	if err := database.DB.Where("id = ?", c.Param("id")).First(record).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("%s not found", entity)})
+		return
+	}
+	before, _ := json.Marshal(record)
+
+	body, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// The primary key always comes from the URL, whatever the body says
+	stmt := &gorm.Statement{DB: database.DB}
+	if err := stmt.Parse(record); err != nil {
+		adminError(c, "update", entity, err)
+		return
+	}
+	value := reflect.Indirect(reflect.ValueOf(record))
+	keys := make([]interface{}, len(stmt.Schema.PrimaryFields))
+	for i, field := range stmt.Schema.PrimaryFields {
+		keys[i], _ = field.ValueOf(c, value)
+	}
+
+	if err := json.Unmarshal(body, record); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	for i, field := range stmt.Schema.PrimaryFields {
+		if err := field.Set(c, value, keys[i]); err != nil {
+			adminError(c, "update", entity, err)
+			return
+		}
+	}
+
+	if err := binding.Validator.ValidateStruct(record); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if hooks.Changed != nil {
+		hooks.Changed(fields)
+	}
+	if hooks.Validate != nil {
+		if err := hooks.Validate(); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Save(record).Error; err != nil {
+			return err
+		}
+		if hooks.AfterSave != nil {
+			if err := hooks.AfterSave(tx); err != nil {
+				return err
+			}
+		}
+		return recordAudit(tx, c, models.AuditUpdate, entity, json.RawMessage(before), record)
+	})
+	if err != nil {
+		adminError(c, "update", entity, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, record)
+}
+
+func adminDelete(c *gin.Context, entity string, record interface{}, hooks adminHooks) {
+	if err := database.DB.Where("id = ?", c.Param("id")).First(record).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("%s not found", entity)})
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if hooks.BeforeDelete != nil {
+			if err := hooks.BeforeDelete(tx); err != nil {
+				return err
+			}
+		}
+		if err := tx.Delete(record).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, c, models.AuditDelete, entity, record, nil)
+	})
+	if err != nil {
+		adminError(c, "delete", entity, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("%s deleted", entity)})
+}
+
+// Banners
+
+func bannerHooks(banner *models.Banner) adminHooks {
+	return adminHooks{
+		Validate: func() error {
+			if banner.Name == "" {
+				return errors.New("name is required")
+			}
+			if banner.EndDate.Before(banner.StartDate) {
+				return errors.New("endDate must not be before startDate")
+			}
+			return nil
+		},
+		BeforeDelete: func(tx *gorm.DB) error {
+			return tx.Where("banner_id = ?", banner.ID).Delete(&models.BannerCharacter{}).Error
+		},
+	}
+}
+
+func AdminCreateBanner(c *gin.Context) {
+	var banner models.Banner
+	adminCreate(c, "banner", &banner, bannerHooks(&banner))
+}
+
+func AdminUpdateBanner(c *gin.Context) {
+	var banner models.Banner
+	adminUpdate(c, "banner", &banner, bannerHooks(&banner))
+}
+
+func AdminDeleteBanner(c *gin.Context) {
+	var banner models.Banner
+	adminDelete(c, "banner", &banner, bannerHooks(&banner))
+}
+
+// Banner characters
+
+func bannerCharacterHooks(bc *models.BannerCharacter) adminHooks {
+	return adminHooks{
+		Validate: func() error {
+			if database.DB.Where("id = ?", bc.BannerID).First(&models.Banner{}).Error != nil {
+				return errors.New("banner not found")
+			}
+			if database.DB.Where("id = ?", bc.CharacterID).First(&models.Character{}).Error != nil {
+				return errors.New("character not found")
+			}
+			return nil
+		},
+	}
+}
+
+func AdminCreateBannerCharacter(c *gin.Context) {
+	var bc models.BannerCharacter
+	adminCreate(c, "banner_character", &bc, bannerCharacterHooks(&bc))
+}
+
+func AdminUpdateBannerCharacter(c *gin.Context) {
+	var bc models.BannerCharacter
+	adminUpdate(c, "banner_character", &bc, bannerCharacterHooks(&bc))
+}
+
+func AdminDeleteBannerCharacter(c *gin.Context) {
+	var bc models.BannerCharacter
+	adminDelete(c, "banner_character", &bc, adminHooks{})
+}
+
+// Codes
+
+func codeHooks(code *models.Code) adminHooks {
+	return adminHooks{
+		Validate: func() error {
+			if code.Code == "" {
+				return errors.New("code is required")
+			}
+			if len(code.RewardItems) == 0 && code.Rewards != "" {
+				code.RewardItems = models.ParseRewards(code.Rewards)
+			}
+			return nil
+		},
+		// Edited reward text replaces the structured items, unless the
+		// body sets both
+		Changed: func(fields map[string]json.RawMessage) {
+			_, rewards := fields["rewards"]
+			_, items := fields["rewardItems"]
+			if rewards && !items {
+				code.RewardItems = models.ParseRewards(code.Rewards)
+			}
+		},
+	}
+}
+
+func AdminCreateCode(c *gin.Context) {
+	var code models.Code
+	adminCreate(c, "code", &code, codeHooks(&code))
+}
+
+func AdminUpdateCode(c *gin.Context) {
+	var code models.Code
+	adminUpdate(c, "code", &code, codeHooks(&code))
+}
+
+func AdminDeleteCode(c *gin.Context) {
+	var code models.Code
+	adminDelete(c, "code", &code, adminHooks{})
+}
+
+// Events
+
+func eventHooks(event *models.Event) adminHooks {
+	return adminHooks{
+		Validate: func() error {
+			if event.Name == "" {
+				return errors.New("name is required")
+			}
+			if event.EndDate.Before(event.StartDate) {
+				return errors.New("endDate must not be before startDate")
+			}
+			return nil
+		},
+	}
+}
+
+func AdminCreateEvent(c *gin.Context) {
+	var event models.Event
+	adminCreate(c, "event", &event, eventHooks(&event))
+}
+
+func AdminUpdateEvent(c *gin.Context) {
+	var event models.Event
+	adminUpdate(c, "event", &event, eventHooks(&event))
+}
+
+func AdminDeleteEvent(c *gin.Context) {
+	var event models.Event
+	adminDelete(c, "event", &event, adminHooks{})
+}
+
+// Characters
+
+func characterHooks(char *models.Character) adminHooks {
+	return adminHooks{
+		Validate: func() error {
+			if char.ID == "" || char.CharID == "" || char.Name == "" {
+				return errors.New("id, charId and name are required")
+			}
+			if database.DB.Where("id = ?", char.ElementID).First(&models.Element{}).Error != nil {
+				return errors.New("element not found")
+			}
+			if database.DB.Where("id = ?", char.PathID).First(&models.Path{}).Error != nil {
+				return errors.New("path not found")
+			}
+			return nil
+		},
+	}
+}
+
+func AdminCreateCharacter(c *gin.Context) {
+	var char models.Character
+	adminCreate(c, "character", &char, characterHooks(&char))
+}
+
+func AdminUpdateCharacter(c *gin.Context) {
+	var char models.Character
+	adminUpdate(c, "character", &char, characterHooks(&char))
+}
+
+// characterRefs are the tables whose rows would break if their character
+// were deleted
+var characterRefs = []struct {
+	Table string
+	Label string
+}{
+	{"user_characters", "users own it"},
+	{"banner_characters", "it's on a banner"},
+	{"team_preset_members", "it's in a team preset"},
+	{"battle_members", "it's in a recorded battle"},
+}
+
+// AdminDeleteCharacter refuses to delete characters that users own or that
+// appear on banners, presets or battles, and otherwise removes the skills,
+// eidolons and build along with the character
+func AdminDeleteCharacter(c *gin.Context) {
+	var char models.Character
+	adminDelete(c, "character", &char, adminHooks{
+		BeforeDelete: func(tx *gorm.DB) error {
+			for _, ref := range characterRefs {
+				var count int64
+				if err := tx.Table(ref.Table).Where("character_id = ?", char.ID).Count(&count).Error; err != nil {
+					return err
+				}
+				if count > 0 {
+					return adminConflict(ref.Label)
+				}
+			}
+			if err := tx.Where("character_id = ?", char.ID).Delete(&models.CharacterSkill{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("character_id = ?", char.ID).Delete(&models.Eidolon{}).Error; err != nil {
+				return err
+			}
+			var build models.CharacterBuild
+			if tx.Where("character_id = ?", char.ID).First(&build).Error == nil {
+				return deleteBuildChildren(tx, build.ID, true)
+			}
+			return nil
+		},
+	})
+}
+
+// Character skills
+
+func characterSkillHooks(skill *models.CharacterSkill) adminHooks {
+	return adminHooks{
+		Validate: func() error {
+			if database.DB.Where("id = ?", skill.CharacterID).First(&models.Character{}).Error != nil {
+				return errors.New("character not found")
+			}
+			if skill.UltCost < 0 || skill.BasicEnergy < 0 || skill.SkillEnergy < 0 {
+				return errors.New("energy values must not be negative")
+			}
+			return nil
+		},
+	}
+}
+
+func AdminCreateCharacterSkill(c *gin.Context) {
+	var skill models.CharacterSkill
+	adminCreate(c, "character_skill", &skill, characterSkillHooks(&skill))
+}
+
+func AdminUpdateCharacterSkill(c *gin.Context) {
+	var skill models.CharacterSkill
+	adminUpdate(c, "character_skill", &skill, characterSkillHooks(&skill))
+}
+
+func AdminDeleteCharacterSkill(c *gin.Context) {
+	var skill models.CharacterSkill
+	adminDelete(c, "character_skill", &skill, adminHooks{})
+}
+
+// Character builds
+
+func deleteBuildChildren(tx *gorm.DB, buildID int, andBuild bool) error {
+	if err := tx.Where("build_id = ?", buildID).Delete(&models.CharacterBuildSet{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("build_id = ?", buildID).Delete(&models.CharacterBuildSubstat{}).Error; err != nil {
+		return err
+	}
+	if andBuild {
+		return tx.Where("id = ?", buildID).Delete(&models.CharacterBuild{}).Error
+	}
+	return nil
+}
+
+// characterBuildHooks replace the build's sets and substats when the body
+// includes them
+func characterBuildHooks(build *models.CharacterBuild) adminHooks {
+	return adminHooks{
+		Validate: func() error {
+			if database.DB.Where("id = ?", build.CharacterID).First(&models.Character{}).Error != nil {
+				return errors.New("character not found")
+			}
+			for _, sub := range build.Substats {
+				if sub.StatName == "" || sub.Weight < 0 || sub.Weight > 1 {
+					return errors.New("substats need a statName and a weight between 0 and 1")
+				}
+			}
+			for _, set := range build.Sets {
+				if database.DB.Where("id = ?", set.RelicSetID).First(&models.RelicSet{}).Error != nil {
+					return fmt.Errorf("relic set %d not found", set.RelicSetID)
+				}
+			}
+			return nil
+		},
+		AfterSave: func(tx *gorm.DB) error {
+			if build.Sets != nil {
+				if err := tx.Where("build_id = ?", build.ID).Delete(&models.CharacterBuildSet{}).Error; err != nil {
+					return err
+				}
+				for i := range build.Sets {
+					build.Sets[i].ID = 0
+					build.Sets[i].BuildID = build.ID
+				}
+				if len(build.Sets) > 0 {
+					if err := tx.Omit(clause.Associations).Create(&build.Sets).Error; err != nil {
+						return err
+					}
+				}
+			}
+			if build.Substats != nil {
+				if err := tx.Where("build_id = ?", build.ID).Delete(&models.CharacterBuildSubstat{}).Error; err != nil {
+					return err
+				}
+				for i := range build.Substats {
+					build.Substats[i].ID = 0
+					build.Substats[i].BuildID = build.ID
+				}
+				if len(build.Substats) > 0 {
+					if err := tx.Omit(clause.Associations).Create(&build.Substats).Error; err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		},
+		BeforeDelete: func(tx *gorm.DB) error {
+			return deleteBuildChildren(tx, build.ID, false)
+		},
+	}
+}
+
+func AdminCreateCharacterBuild(c *gin.Context) {
+	var build models.CharacterBuild
+	adminCreate(c, "character_build", &build, characterBuildHooks(&build))
+}
+
+func AdminUpdateCharacterBuild(c *gin.Context) {
+	var build models.CharacterBuild
+	adminUpdate(c, "character_build", &build, characterBuildHooks(&build))
+}
+
+func AdminDeleteCharacterBuild(c *gin.Context) {
+	var build models.CharacterBuild
+	adminDelete(c, "character_build", &build, characterBuildHooks(&build))
+}
+
+// Users
+
+// AdminSetUserRole grants or revokes admin. Admins can't demote themselves,
+// so there's always at least one admin left.
+func AdminSetUserRole(c *gin.Context) {
+	var req struct {
+		Role string `json:"role" binding:"required,oneof=user admin"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	if c.Param("id") == userID.(uuid.UUID).String() && req.Role != models.RoleAdmin {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You can't remove your own admin role"})
+		return
+	}
+
+	var user models.User
+	if err := database.DB.Where("id = ?", c.Param("id")).First(&user).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	before := user
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&user).Update("role", req.Role).Error; err != nil {
+			return err
+		}
+		user.Role = req.Role
+		return recordAudit(tx, c, models.AuditUpdate, "user", before, user)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update role"})
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// Audit log
+
+func GetAuditLogs(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "page must be a positive integer"})
+		return
+	}
+	pageSize, err := strconv.Atoi(c.DefaultQuery("pageSize", "50"))
+	if err != nil || pageSize < 1 || pageSize > 200 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "pageSize must be between 1 and 200"})
+		return
+	}
+
+	query := database.DB.Model(&models.AuditLog{})
+	if entity := c.Query("entity"); entity != "" {
+		query = query.Where("entity = ?", entity)
+	}
+	if entityID := c.Query("entityId"); entityID != "" {
+		query = query.Where("entity_id = ?", entityID)
+	}
+	if userID := c.Query("userId"); userID != "" {
+		query = query.Where("user_id = ?", userID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch audit log"})
+		return
+	}
+
+	logs := []models.AuditLog{}
+	if err := query.Preload("User").Order("created_at DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&logs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch audit log"})
+		return
+	}
+
+	c.JSON(http.StatusOK, AuditLogPage{
+		Data:     logs,
+		Page:     page,
+		PageSize: pageSize,
+		Total:    total,
+	})
+}
@@ -1,14 +1,15 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/hsr-tools/backend/internal/database"
 	"github.com/hsr-tools/backend/internal/models"
-	"github.com/hsr-tools/backend/pkg/utils"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
 type RegisterRequest struct {
@@ -60,13 +61,18 @@ func Register(c *gin.Context) {
 		return
 	}
 
+	sendVerificationEmail(user)
+
 	// Generate tokens
-	token, _ := utils.GenerateToken(user.ID, user.Email)
-	refreshToken, _ := utils.GenerateRefreshToken(user.ID, user.Email)
+	tokens, _, err := issueTokens(database.DB, c, user, uuid.Nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create session"})
+		return
+	}
 
 	c.JSON(http.StatusCreated, AuthResponse{
-		Token:        token,
-		RefreshToken: refreshToken,
+		Token:        tokens.Token,
+		RefreshToken: tokens.RefreshToken,
 		User:         user,
 	})
 }
@@ -89,12 +95,17 @@ func Login(c *gin.Context) {
 		return
 	}
 
-	token, _ := utils.GenerateToken(user.ID, user.Email)
-	refreshToken, _ := utils.GenerateRefreshToken(user.ID, user.Email)
+	tokens, _, err := issueTokens(database.DB, c, user, uuid.Nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create session"})
+		return
+	}
+
+	recordActivity(user.ID, models.ActivityLogin, gin.H{"ip": c.ClientIP()})
 
 	c.JSON(http.StatusOK, AuthResponse{
-		Token:        token,
-		RefreshToken: refreshToken,
+		Token:        tokens.Token,
+		RefreshToken: tokens.RefreshToken,
 		User:         user,
 	})
 }
@@ -108,19 +119,20 @@ func RefreshToken(c *gin.Context) {
 		return
 	}
 
-	claims, err := utils.ValidateToken(req.RefreshToken)
-	if err != nil {
+	tokens, err := rotateRefreshToken(c, req.RefreshToken)
+	switch {
+	case errors.Is(err, errRefreshReused):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Refresh token reuse detected, please log in again"})
+		return
+	case errors.Is(err, errRefreshInvalid):
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid refresh token"})
 		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to refresh token"})
+		return
 	}
 
-	token, _ := utils.GenerateToken(claims.UserID, claims.Email)
-	refreshToken, _ := utils.GenerateRefreshToken(claims.UserID, claims.Email)
-
-	c.JSON(http.StatusOK, gin.H{
-		"token":        token,
-		"refreshToken": refreshToken,
-	})
+	c.JSON(http.StatusOK, tokens)
 }
 
 func GetCurrentUser(c *gin.Context) {
@@ -161,6 +173,8 @@ func SetUID(c *gin.Context) {
 		return
 	}
 
+	recordActivity(user.ID, models.ActivityUIDSet, gin.H{"uid": user.UID})
+
 	c.JSON(http.StatusOK, user)
 }
 
@@ -168,7 +182,7 @@ func GetUserCharacters(c *gin.Context) {
 	userID, _ := c.Get("userID")
 
 	var characters []models.UserCharacter
-	database.DB.Preload("Character.Element").Preload("Character.Path").
+	database.DB.Preload("Character.Element").Preload("Character.Path").Preload("LightCone").
 		Where("user_id = ?", userID).Find(&characters)
 
 	c.JSON(http.StatusOK, characters)
@@ -179,8 +193,10 @@ func AddUserCharacter(c *gin.Context) {
 	uid := userID.(uuid.UUID)
 
 	var req struct {
-		CharacterID string `json:"characterId" binding:"required"`
-		Eidolon     int    `json:"eidolon"`
+		CharacterID     string  `json:"characterId" binding:"required"`
+		Eidolon         int     `json:"eidolon" binding:"min=0,max=6"`
+		LightConeID     *string `json:"lightConeId"`
+		Superimposition int     `json:"superimposition"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -194,10 +210,25 @@ func AddUserCharacter(c *gin.Context) {
 		return
 	}
 
+	// An empty ID means no light cone, which the column stores as NULL
+	if req.LightConeID != nil && *req.LightConeID == "" {
+		req.LightConeID = nil
+	}
+	// A light cone without an explicit rank is S1
+	if req.LightConeID != nil && req.Superimposition == 0 {
+		req.Superimposition = 1
+	}
+	if status, msg := validateLightCone(req.LightConeID, req.Superimposition); status != 0 {
+		c.JSON(status, gin.H{"error": msg})
+		return
+	}
+
 	userChar := models.UserCharacter{
-		UserID:      uid,
-		CharacterID: req.CharacterID,
-		Eidolon:     req.Eidolon,
+		UserID:          uid,
+		CharacterID:     req.CharacterID,
+		Eidolon:         req.Eidolon,
+		LightConeID:     req.LightConeID,
+		Superimposition: req.Superimposition,
 	}
 
 	// Upsert
@@ -217,18 +248,68 @@ func UpdateUserCharacter(c *gin.Context) {
 	charID := c.Param("id")
 
 	var req struct {
-		Eidolon int `json:"eidolon"`
-		Level   int `json:"level"`
+		Eidolon         int     `json:"eidolon" binding:"min=0,max=6"`
+		Level           int     `json:"level"`
+		LightConeID     *string `json:"lightConeId"`
+		Superimposition *int    `json:"superimposition"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
+	var existing models.UserCharacter
+	if err := database.DB.Where("user_id = ? AND character_id = ?", userID, charID).First(&existing).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Character not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update character"})
+		}
+		return
+	}
+
+	updates := map[string]interface{}{"eidolon": req.Eidolon, "level": req.Level}
+
+	// Light cone fields are only touched when sent; an empty ID unequips
+	if req.LightConeID != nil {
+		superimposition := 1
+		if req.Superimposition != nil {
+			superimposition = *req.Superimposition
+		}
+		if *req.LightConeID == "" {
+			superimposition = 0
+		}
+		if status, msg := validateLightCone(req.LightConeID, superimposition); status != 0 {
+			c.JSON(status, gin.H{"error": msg})
+			return
+		}
+
+		updates["light_cone_id"] = req.LightConeID
+		if *req.LightConeID == "" {
+			updates["light_cone_id"] = nil
+		}
+		updates["superimposition"] = superimposition
+	} else if req.Superimposition != nil {
+		// Superimposition stays 0 without a light cone
+		if existing.LightConeID == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Superimposition requires a light cone"})
+			return
+		}
+		if *req.Superimposition < 1 || *req.Superimposition > 5 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Superimposition must be between 1 and 5"})
+			return
+		}
+		updates["superimposition"] = *req.Superimposition
+	}
+
 	result := database.DB.Model(&models.UserCharacter{}).
 		Where("user_id = ? AND character_id = ?", userID, charID).
-		Updates(map[string]interface{}{"eidolon": req.Eidolon, "level": req.Level})
+		Updates(updates)
 
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update character"})
+		return
+	}
 	if result.RowsAffected == 0 {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Character not found"})
 		return
@@ -0,0 +1,323 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/hsr-tools/backend/internal/database"
+	"github.com/hsr-tools/backend/internal/engine"
+	"github.com/hsr-tools/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type BattleMemberRequest struct {
+	CharacterID string   `json:"characterId" binding:"required"`
+	Speed       *float64 `json:"speed" binding:"omitempty,gt=0,lte=1000"`
+	Atk         *float64 `json:"atk" binding:"omitempty,gt=0,lte=100000"`
+	CritRate    *float64 `json:"critRate" binding:"omitempty,gte=0,lte=1"`
+	CritDmg     *float64 `json:"critDmg" binding:"omitempty,gte=0,lte=10"`
+	DmgBonus    *float64 `json:"dmgBonus" binding:"omitempty,gte=0,lte=10"`
+}
+
+type BattleSimulateRequest struct {
+	Team      []BattleMemberRequest `json:"team" binding:"required,min=1,max=4,dive"`
+	EnemyID   string                `json:"enemyId"`
+	Enemy     engine.EnemyConfig    `json:"enemy"`
+	Seed      *int64                `json:"seed"`
+	MaxCycles int                   `json:"maxCycles" binding:"omitempty,min=1,max=100"`
+}
+
+func SimulateBattle(c *gin.Context) {
+	var req BattleSimulateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	team, err := loadCombatants(req.Team)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	enemy := req.Enemy
+	if req.EnemyID != "" {
+		if enemy, err = loadEnemyConfig(req.EnemyID); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	// Random seed when none is given; it is returned so the run can be replayed
+	seed := time.Now().UnixNano()
+	if req.Seed != nil {
+		seed = *req.Seed
+	}
+
+	result, err := engine.Simulate(engine.BattleConfig{
+		Team:      team,
+		Enemy:     enemy,
+		Seed:      seed,
+		MaxCycles: req.MaxCycles,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// loadCombatants resolves team members against the character and skill tables
+func loadCombatants(members []BattleMemberRequest) ([]engine.Combatant, error) {
+	team := make([]engine.Combatant, 0, len(members))
+	for _, m := range members {
+		var char models.Character
+		if err := database.DB.Preload("Element").Preload("Skills").
+			Where("id = ?", m.CharacterID).First(&char).Error; err != nil || char.Skills == nil {
+			return nil, fmt.Errorf("Character not found: %s", m.CharacterID)
+		}
+
+		combatant := engine.NewCombatant(char, *char.Skills)
+		if m.Speed != nil {
+			combatant.Speed = *m.Speed
+		}
+		if m.Atk != nil {
+			combatant.Attacker.BaseAtk = *m.Atk
+		}
+		if m.CritRate != nil {
+			combatant.Attacker.CritRate = *m.CritRate
+		}
+		if m.CritDmg != nil {
+			combatant.Attacker.CritDmg = *m.CritDmg
+		}
+		if m.DmgBonus != nil {
+			combatant.Attacker.DmgBonus = *m.DmgBonus
+		}
+		team = append(team, combatant)
+	}
+	return team, nil
+}
+
+type BattleRecordRequest struct {
+	Team        []BattleMemberRequest `json:"team" binding:"required,min=1,max=4,dive"`
+	EnemyID     string                `json:"enemyId" binding:"required"`
+	Result      string                `json:"result" binding:"required,oneof=victory defeat abandoned"`
+	TotalDamage int                   `json:"totalDamage" binding:"min=0"`
+	Turns       int                   `json:"turns" binding:"min=0"`
+	Duration    *int                  `json:"duration" binding:"omitempty,min=0"`
+	Seed        *int64                `json:"seed"`
+	MaxCycles   int                   `json:"maxCycles" binding:"omitempty,min=1,max=100"`
+}
+
+type LeaderboardEntry struct {
+	Rank        int       `json:"rank"`
+	BattleID    uuid.UUID `json:"battleId"`
+	Player      string    `json:"player"`
+	Team        []string  `json:"team"`
+	TotalDamage int       `json:"totalDamage"`
+	Turns       int       `json:"turns"`
+	ActionValue *float64  `json:"actionValue"`
+	Result      string    `json:"result"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// validateBattle replays a run through the simulator. A run is valid only
+// when it was seeded and the replay reproduces the claimed outcome.
+func validateBattle(req BattleRecordRequest, team []engine.Combatant, enemy engine.EnemyConfig) (engine.BattleResult, bool) {
+	if req.Seed == nil || req.Result == "abandoned" {
+		return engine.BattleResult{}, false
+	}
+
+	result, err := engine.Simulate(engine.BattleConfig{
+		Team:      team,
+		Enemy:     enemy,
+		Seed:      *req.Seed,
+		MaxCycles: req.MaxCycles,
+	})
+	if err != nil {
+		return engine.BattleResult{}, false
+	}
+
+	valid := result.TotalDamage == req.TotalDamage &&
+		result.Turns == req.Turns &&
+		result.Victory == (req.Result == "victory")
+	return result, valid
+}
+
+// hasOverrides reports whether any member replaces the base stats. Those
+// values come from the client, so runs using them can't be ranked.
+func hasOverrides(members []BattleMemberRequest) bool {
+	for _, m := range members {
+		if m.Speed != nil || m.Atk != nil || m.CritRate != nil || m.CritDmg != nil || m.DmgBonus != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func RecordBattle(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var req BattleRecordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	team, err := loadCombatants(req.Team)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	enemy, err := loadEnemyConfig(req.EnemyID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	members := make([]models.BattleMember, len(req.Team))
+	for i, m := range req.Team {
+		members[i] = models.BattleMember{
+			Slot:        i + 1,
+			CharacterID: m.CharacterID,
+			Speed:       m.Speed,
+			Atk:         m.Atk,
+			CritRate:    m.CritRate,
+			CritDmg:     m.CritDmg,
+			DmgBonus:    m.DmgBonus,
+		}
+	}
+
+	replay, validated := validateBattle(req, team, enemy)
+
+	battle := models.Battle{
+		UserID:      userID.(uuid.UUID),
+		EnemyID:     req.EnemyID,
+		Result:      req.Result,
+		TotalDamage: req.TotalDamage,
+		Turns:       req.Turns,
+		Duration:    req.Duration,
+		Seed:        req.Seed,
+		MaxCycles:   req.MaxCycles,
+		Validated:   validated,
+		Ranked:      validated && !hasOverrides(req.Team),
+		Team:        members,
+	}
+	if validated {
+		battle.ActionValue = &replay.ActionValue
+	}
+
+	if err := database.DB.Create(&battle).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record battle"})
+		return
+	}
+
+	recordActivity(battle.UserID, models.ActivityBattle, gin.H{
+		"battleId":    battle.ID,
+		"enemyId":     battle.EnemyID,
+		"result":      battle.Result,
+		"totalDamage": battle.TotalDamage,
+	})
+
+	c.JSON(http.StatusCreated, battle)
+}
+
+func GetUserBattles(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var battles []models.Battle
+	query := database.DB.Preload("Team", func(db *gorm.DB) *gorm.DB {
+		return db.Order("slot ASC")
+	}).Where("user_id = ?", userID)
+
+	if enemy := c.Query("enemy"); enemy != "" {
+		query = query.Where("enemy_id = ?", enemy)
+	}
+
+	if err := query.Order("created_at DESC").Find(&battles).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch battles"})
+		return
+	}
+
+	c.JSON(http.StatusOK, battles)
+}
+
+func GetBattleLeaderboard(c *gin.Context) {
+	enemyID := c.Query("enemy")
+	if enemyID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "enemy is required"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 100"})
+		return
+	}
+
+	// Only ranked runs count, i.e. replayed from base stats
+	query := database.DB.Preload("User").Preload("Team", func(db *gorm.DB) *gorm.DB {
+		return db.Order("slot ASC")
+	}).Where("enemy_id = ? AND ranked = ?", enemyID, true)
+
+	switch c.DefaultQuery("sort", "damage") {
+	case "damage":
+		// Damage only compares between runs given the same number of cycles
+		cycles, err := strconv.Atoi(c.DefaultQuery("cycles", strconv.Itoa(engine.DefaultMaxCycles)))
+		if err != nil || cycles < 1 || cycles > engine.MaxAllowedCycles {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("cycles must be between 1 and %d", engine.MaxAllowedCycles)})
+			return
+		}
+		if cycles == engine.DefaultMaxCycles {
+			query = query.Where("max_cycles IN ?", []int{0, cycles})
+		} else {
+			query = query.Where("max_cycles = ?", cycles)
+		}
+		query = query.Order("total_damage DESC").Order("action_value ASC")
+	case "turns":
+		// Damage is capped by the enemy's HP, so among clears the fastest wins
+		query = query.Where("result = ?", "victory").Order("turns ASC").Order("action_value ASC")
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "sort must be 'damage' or 'turns'"})
+		return
+	}
+
+	var battles []models.Battle
+	if err := query.Order("created_at ASC").Limit(limit).Find(&battles).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch leaderboard"})
+		return
+	}
+
+	entries := make([]LeaderboardEntry, len(battles))
+	for i, b := range battles {
+		team := make([]string, len(b.Team))
+		for j, m := range b.Team {
+			team[j] = m.CharacterID
+		}
+
+		player := b.User.Nickname
+		if player == "" {
+			player = b.User.Name
+		}
+
+		entries[i] = LeaderboardEntry{
+			Rank:        i + 1,
+			BattleID:    b.ID,
+			Player:      player,
+			Team:        team,
+			TotalDamage: b.TotalDamage,
+			Turns:       b.Turns,
+			ActionValue: b.ActionValue,
+			Result:      b.Result,
+			CreatedAt:   b.CreatedAt,
+		}
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gin-gonic/gin/binding"
+)
+
+func TestBattleSimulateRequestBounds(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr bool
+	}{
+		{name: "no overrides", body: `{"team":[{"characterId":"seele"}]}`},
+		{name: "valid overrides", body: `{"team":[{"characterId":"seele","speed":160,"atk":3000,"critRate":0.7,"critDmg":1.8,"dmgBonus":0.5}]}`},
+		{name: "zero crit rate", body: `{"team":[{"characterId":"seele","critRate":0}]}`},
+		{name: "zero speed", body: `{"team":[{"characterId":"seele","speed":0}]}`, wantErr: true},
+		{name: "negative speed", body: `{"team":[{"characterId":"seele","speed":-1}]}`, wantErr: true},
+		{name: "huge speed", body: `{"team":[{"characterId":"seele","speed":1e12}]}`, wantErr: true},
+		{name: "crit rate over 100%", body: `{"team":[{"characterId":"seele","critRate":1.5}]}`, wantErr: true},
+		{name: "negative atk", body: `{"team":[{"characterId":"seele","atk":-100}]}`, wantErr: true},
+		{name: "enemy speed", body: `{"team":[{"characterId":"seele"}],"enemy":{"hp":1000,"speed":90}}`},
+		{name: "negative enemy speed", body: `{"team":[{"characterId":"seele"}],"enemy":{"hp":1000,"speed":-5}}`, wantErr: true},
+		{name: "huge enemy speed", body: `{"team":[{"characterId":"seele"}],"enemy":{"hp":1000,"speed":1e9}}`, wantErr: true},
+		{name: "empty team", body: `{"team":[]}`, wantErr: true},
+		{name: "too many cycles", body: `{"team":[{"characterId":"seele"}],"maxCycles":1000}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req BattleSimulateRequest
+			if err := json.Unmarshal([]byte(tt.body), &req); err != nil {
+				t.Fatal(err)
+			}
+			err := binding.Validator.ValidateStruct(&req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateStruct() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
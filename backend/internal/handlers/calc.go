@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hsr-tools/backend/internal/database"
+	"github.com/hsr-tools/backend/internal/engine"
+	"github.com/hsr-tools/backend/internal/models"
+)
+
+type DamageCalcRequest struct {
+	CharacterID string        `json:"characterId" binding:"required"`
+	Action      string        `json:"action" binding:"omitempty,oneof=basic skill ultimate"`
+	Level       int           `json:"level" binding:"omitempty,min=1,max=80"`
+	DmgBonus    float64       `json:"dmgBonus"`
+	Enemy       engine.Target `json:"enemy"`
+	Buffs       []engine.Buff `json:"buffs"`
+}
+
+type DamageCalcResponse struct {
+	CharacterID string              `json:"characterId"`
+	Action      string              `json:"action"`
+	Element     string              `json:"element"`
+	NonCrit     int                 `json:"nonCrit"`
+	Crit        int                 `json:"crit"`
+	Expected    int                 `json:"expected"`
+	Result      engine.DamageResult `json:"result"`
+}
+
+func CalculateDamage(c *gin.Context) {
+	var req DamageCalcRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var character models.Character
+	if err := database.DB.Preload("Element").Preload("Skills").
+		Where("id = ?", req.CharacterID).First(&character).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Character not found"})
+		return
+	}
+	if character.Skills == nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Character has no skill data"})
+		return
+	}
+
+	action := engine.ActionType(req.Action)
+	if action == "" {
+		action = engine.ActionBasic
+	}
+
+	attacker := engine.AttackerFromSkill(*character.Skills, character.Element.Name)
+	attacker.DmgBonus = req.DmgBonus
+	if req.Level > 0 {
+		attacker.Level = req.Level
+	}
+
+	result := engine.CalculateDamage(attacker, req.Enemy, engine.Multiplier(*character.Skills, action), req.Buffs)
+
+	c.JSON(http.StatusOK, DamageCalcResponse{
+		CharacterID: character.ID,
+		Action:      string(action),
+		Element:     character.Element.Name,
+		NonCrit:     result.NonCrit,
+		Crit:        result.Crit,
+		Expected:    result.Expected,
+		Result:      result,
+	})
+}
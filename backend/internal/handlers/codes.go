@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/hsr-tools/backend/internal/database"
+	"github.com/hsr-tools/backend/internal/models"
+	"gorm.io/gorm/clause"
+)
+
+// GetUserCodes returns the active, unexpired codes the user hasn't redeemed
+func GetUserCodes(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	codes := []models.Code{}
+	if err := database.DB.
+		Where("is_active = ? AND (expires_at IS NULL OR expires_at > ?)", true, time.Now()).
+		Where("id NOT IN (?)", database.DB.Model(&models.UserRedeemedCode{}).
+			Select("code_id").
+			Where("user_id = ?", userID)).
+		Order("created_at DESC").
+		Find(&codes).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch codes"})
+		return
+	}
+
+	c.JSON(http.StatusOK, codes)
+}
+
+// GetRedeemedCodes returns the user's redemption history
+func GetRedeemedCodes(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	redeemed := []models.UserRedeemedCode{}
+	if err := database.DB.Preload("Code").
+		Where("user_id = ?", userID).
+		Order("redeemed_at DESC").
+		Find(&redeemed).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch redeemed codes"})
+		return
+	}
+
+	c.JSON(http.StatusOK, redeemed)
+}
+
+func findCode(code string) (models.Code, error) {
+	var found models.Code
+	err := database.DB.Where("UPPER(code) = ?", strings.ToUpper(code)).First(&found).Error
+	return found, err
+}
+
+// RedeemCode marks a code as redeemed. Marking it twice is not an error.
+func RedeemCode(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	code, err := findCode(c.Param("code"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Code not found"})
+		return
+	}
+
+	redeemed := models.UserRedeemedCode{
+		UserID: userID.(uuid.UUID),
+		CodeID: code.ID,
+	}
+	if err := database.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&redeemed).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to redeem code"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Code marked as redeemed", "code": code})
+}
+
+// UnredeemCode removes the redeemed mark, e.g. after a mis-click
+func UnredeemCode(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	code, err := findCode(c.Param("code"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Code not found"})
+		return
+	}
+
+	if err := database.DB.Where("user_id = ? AND code_id = ?", userID, code.ID).
+		Delete(&models.UserRedeemedCode{}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update code"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Code marked as not redeemed"})
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hsr-tools/backend/internal/database"
+	"github.com/hsr-tools/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type UserCharacterEidolonsResponse struct {
+	CharacterID string           `json:"characterId"`
+	Eidolon     int              `json:"eidolon"`
+	Active      []models.Eidolon `json:"active"`
+	Locked      []models.Eidolon `json:"locked"`
+}
+
+// splitEidolons separates the eidolons unlocked at rank from the rest
+func splitEidolons(eidolons []models.Eidolon, rank int) ([]models.Eidolon, []models.Eidolon) {
+	active := []models.Eidolon{}
+	locked := []models.Eidolon{}
+	for _, e := range eidolons {
+		if e.Rank <= rank {
+			active = append(active, e)
+		} else {
+			locked = append(locked, e)
+		}
+	}
+	return active, locked
+}
+
+func GetUserCharacterEidolons(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	charID := c.Param("id")
+
+	var userChar models.UserCharacter
+	if err := database.DB.
+		Preload("Character.Eidolons", func(db *gorm.DB) *gorm.DB {
+			return db.Order("rank ASC")
+		}).
+		Where("user_id = ? AND character_id = ?", userID, charID).
+		First(&userChar).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Character not found"})
+		return
+	}
+
+	active, locked := splitEidolons(userChar.Character.Eidolons, userChar.Eidolon)
+
+	c.JSON(http.StatusOK, UserCharacterEidolonsResponse{
+		CharacterID: userChar.CharacterID,
+		Eidolon:     userChar.Eidolon,
+		Active:      active,
+		Locked:      locked,
+	})
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hsr-tools/backend/internal/database"
+	"github.com/hsr-tools/backend/internal/engine"
+	"github.com/hsr-tools/backend/internal/models"
+)
+
+type EnemyResponse struct {
+	ID         string                `json:"id"`
+	Name       string                `json:"name"`
+	Type       string                `json:"type"`
+	HP         int                   `json:"hp"`
+	Speed      int                   `json:"speed"`
+	Def        int                   `json:"def"`
+	Toughness  int                   `json:"toughness"`
+	ImageURL   string                `json:"imageUrl"`
+	Weakness   []string              `json:"weakness"`
+	Resistance map[string]float64    `json:"resistance"`
+	Variants   []models.EnemyVariant `json:"variants"`
+}
+
+func toEnemyResponse(enemy models.Enemy) EnemyResponse {
+	weakness := make([]string, len(enemy.Weaknesses))
+	for i, w := range enemy.Weaknesses {
+		weakness[i] = w.Element.Name
+	}
+
+	resistance := make(map[string]float64, len(enemy.Resistances))
+	for _, r := range enemy.Resistances {
+		resistance[r.Element.Name] = r.Value
+	}
+
+	variants := enemy.Variants
+	if variants == nil {
+		variants = []models.EnemyVariant{}
+	}
+
+	return EnemyResponse{
+		ID:         enemy.ID,
+		Name:       enemy.Name,
+		Type:       enemy.Type,
+		HP:         enemy.HP,
+		Speed:      enemy.Speed,
+		Def:        enemy.Def,
+		Toughness:  enemy.Toughness,
+		ImageURL:   enemy.ImageURL,
+		Weakness:   weakness,
+		Resistance: resistance,
+		Variants:   variants,
+	}
+}
+
+func GetEnemies(c *gin.Context) {
+	var enemies []models.Enemy
+
+	query := database.DB.
+		Preload("Weaknesses.Element").
+		Preload("Resistances.Element").
+		Preload("Variants")
+
+	// Optional filters
+	if weakness := c.Query("weakness"); weakness != "" {
+		query = query.Where("enemies.id IN (?)", database.DB.Table("enemy_weaknesses").
+			Select("enemy_weaknesses.enemy_id").
+			Joins("JOIN elements ON enemy_weaknesses.element_id = elements.id").
+			Where("elements.name = ?", weakness))
+	}
+	if enemyType := c.Query("type"); enemyType != "" {
+		query = query.Where("type = ?", enemyType)
+	}
+
+	if err := query.Order("name ASC").Find(&enemies).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch enemies"})
+		return
+	}
+
+	response := make([]EnemyResponse, len(enemies))
+	for i, enemy := range enemies {
+		response[i] = toEnemyResponse(enemy)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func GetEnemyByID(c *gin.Context) {
+	id := c.Param("id")
+
+	var enemy models.Enemy
+	if err := database.DB.
+		Preload("Weaknesses.Element").
+		Preload("Resistances.Element").
+		Preload("Variants").
+		Where("id = ?", id).
+		First(&enemy).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Enemy not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, toEnemyResponse(enemy))
+}
+
+// loadEnemyConfig resolves a catalog enemy into simulator input
+func loadEnemyConfig(id string) (engine.EnemyConfig, error) {
+	var enemy models.Enemy
+	if err := database.DB.
+		Preload("Weaknesses.Element").
+		Preload("Resistances.Element").
+		Where("id = ?", id).
+		First(&enemy).Error; err != nil {
+		return engine.EnemyConfig{}, fmt.Errorf("Enemy not found: %s", id)
+	}
+
+	resp := toEnemyResponse(enemy)
+	return engine.EnemyConfig{
+		ID:         resp.ID,
+		Name:       resp.Name,
+		HP:         resp.HP,
+		Speed:      float64(resp.Speed),
+		Def:        float64(resp.Def),
+		Level:      engine.DefaultEnemyLevel,
+		Toughness:  resp.Toughness,
+		Weakness:   resp.Weakness,
+		Resistance: resp.Resistance,
+	}, nil
+}
@@ -1,13 +1,16 @@
 package handlers
 
 import (
-	"io"
+	"errors"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/hsr-tools/backend/internal/database"
+	"github.com/hsr-tools/backend/internal/mihomo"
 	"github.com/hsr-tools/backend/internal/models"
+	"gorm.io/gorm"
 )
 
 type CharacterListResponse struct {
@@ -77,6 +80,9 @@ func GetCharacterByID(c *gin.Context) {
 		Preload("Skills").
 		Preload("Build.Substats").
 		Preload("Build.Sets.RelicSet").
+		Preload("Eidolons", func(db *gorm.DB) *gorm.DB {
+			return db.Order("rank ASC")
+		}).
 		Where("id = ?", id).
 		First(&character).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Character not found"})
@@ -110,9 +116,10 @@ func GetCodes(c *gin.Context) {
 
 	query := database.DB
 
-	// Only active by default
+	// Only active, unexpired codes by default. The expiry job flips
+	// IsActive periodically, so expiry is also checked here.
 	if c.Query("all") != "true" {
-		query = query.Where("is_active = ?", true)
+		query = query.Where("is_active = ? AND (expires_at IS NULL OR expires_at > ?)", true, time.Now())
 	}
 
 	if err := query.Order("created_at DESC").Find(&codes).Error; err != nil {
@@ -145,19 +152,24 @@ func GetEvents(c *gin.Context) {
 func GetMihomoProfile(c *gin.Context) {
 	uid := c.Param("uid")
 
-	// Proxy request to Mihomo API
-	resp, err := http.Get("https://api.mihomo.me/sr_info_parsed/" + uid + "?lang=en")
+	profile, err := mihomo.Profiles.Get(c.Request.Context(), uid)
 	if err != nil {
-		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to fetch from Mihomo API"})
+		// Raw proxy keeps passing upstream errors through unchanged
+		var upstream *mihomo.UpstreamError
+		if errors.As(err, &upstream) {
+			c.Data(upstream.StatusCode, "application/json", upstream.Body)
+			return
+		}
+		writeProfileError(c, err)
 		return
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read response"})
-		return
+	// Signed-in users get the refresh recorded in their feed
+	if userID, ok := c.Get("userID"); ok && profile.Cache == mihomo.CacheMiss {
+		recordActivity(userID.(uuid.UUID), models.ActivityProfileRefresh, gin.H{"uid": uid})
 	}
 
-	c.Data(resp.StatusCode, "application/json", body)
+	c.Header("X-Cache", profile.Cache)
+	c.Header("Last-Modified", profile.FetchedAt.UTC().Format(http.TimeFormat))
+	c.Data(http.StatusOK, "application/json", profile.Data)
 }
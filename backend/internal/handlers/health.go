@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hsr-tools/backend/internal/database"
+)
+
+// readinessTimeout bounds the database checks so a hung connection fails
+// the probe instead of stalling it
+const readinessTimeout = 2 * time.Second
+
+// shuttingDown is set once the server starts draining
+var shuttingDown atomic.Bool
+
+// MarkShuttingDown makes readiness fail, so load balancers stop routing new
+// requests here while in-flight ones finish
+func MarkShuttingDown() {
+	shuttingDown.Store(true)
+}
+
+// Healthz is the liveness probe. It only checks that the process is serving
+// requests, so a database outage doesn't get every instance restarted.
+func Healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// Readyz is the readiness probe. It fails while shutting down, when the
+// database can't be reached, or when migrations this build needs haven't
+// been applied yet. Applied migrations this build doesn't know about are
+// fine, since older instances keep serving during a deploy.
+func Readyz(c *gin.Context) {
+	ready := true
+	checks := gin.H{}
+
+	if shuttingDown.Load() {
+		ready = false
+		checks["server"] = "shutting down"
+	} else {
+		checks["server"] = "ok"
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), readinessTimeout)
+	defer cancel()
+
+	if err := pingDatabase(ctx); err != nil {
+		// Driver errors name hosts and users, so they only go to the log
+		log.Printf("Warning: readiness database check failed: %v", err)
+		ready = false
+		checks["database"] = "unavailable"
+	} else {
+		checks["database"] = "ok"
+		migrations, ok := migrationState(ctx)
+		checks["migrations"] = migrations
+		ready = ready && ok
+	}
+
+	if !ready {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "unavailable", "checks": checks})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "ready", "checks": checks})
+}
+
+func pingDatabase(ctx context.Context) error {
+	sqlDB, err := database.DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// migrationState reports the schema version and whether every migration
+// this build ships with has been applied
+func migrationState(ctx context.Context) (gin.H, bool) {
+	statuses, err := database.MigrationStatuses(ctx)
+	if err != nil {
+		log.Printf("Warning: readiness migration check failed: %v", err)
+		return gin.H{"error": "unavailable"}, false
+	}
+
+	var version int64
+	pending, unknown := 0, 0
+	for _, s := range statuses {
+		switch {
+		case s.AppliedAt == nil:
+			pending++
+		case s.Missing:
+			unknown++
+		}
+		if s.AppliedAt != nil && s.Version > version {
+			version = s.Version
+		}
+	}
+	return gin.H{"version": version, "pending": pending, "unknown": unknown}, pending == 0
+}
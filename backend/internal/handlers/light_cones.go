@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hsr-tools/backend/internal/database"
+	"github.com/hsr-tools/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type LightConeListResponse struct {
+	ID     string `json:"id"`
+	GameID string `json:"gameId"`
+	Name   string `json:"name"`
+	Path   string `json:"path"`
+	Rarity int    `json:"rarity"`
+	MaxHP  int    `json:"maxHp"`
+	MaxAtk int    `json:"maxAtk"`
+	MaxDef int    `json:"maxDef"`
+}
+
+func GetLightCones(c *gin.Context) {
+	var lightCones []models.LightCone
+
+	query := database.DB.Preload("Path")
+
+	// Optional filters
+	if path := c.Query("path"); path != "" {
+		query = query.Joins("JOIN paths ON light_cones.path_id = paths.id").
+			Where("paths.name = ?", path)
+	}
+	if rarity := c.Query("rarity"); rarity != "" {
+		query = query.Where("rarity = ?", rarity)
+	}
+
+	if err := query.Order("rarity DESC").Order("light_cones.name ASC").Find(&lightCones).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch light cones"})
+		return
+	}
+
+	response := make([]LightConeListResponse, len(lightCones))
+	for i, lc := range lightCones {
+		response[i] = LightConeListResponse{
+			ID:     lc.ID,
+			GameID: lc.GameID,
+			Name:   lc.Name,
+			Path:   lc.Path.Name,
+			Rarity: lc.Rarity,
+			MaxHP:  lc.MaxHP,
+			MaxAtk: lc.MaxAtk,
+			MaxDef: lc.MaxDef,
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func GetLightConeByID(c *gin.Context) {
+	id := c.Param("id")
+
+	var lightCone models.LightCone
+	if err := database.DB.
+		Preload("Path").
+		Preload("Superimpositions", func(db *gorm.DB) *gorm.DB {
+			return db.Order("rank ASC")
+		}).
+		Where("id = ?", id).
+		First(&lightCone).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Light cone not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, lightCone)
+}
+
+// validateLightCone checks an owned character's light cone and rank
+func validateLightCone(lightConeID *string, superimposition int) (int, string) {
+	if lightConeID == nil || *lightConeID == "" {
+		if superimposition != 0 {
+			return http.StatusBadRequest, "Superimposition requires a light cone"
+		}
+		return 0, ""
+	}
+	if superimposition < 1 || superimposition > 5 {
+		return http.StatusBadRequest, "Superimposition must be between 1 and 5"
+	}
+
+	var lightCone models.LightCone
+	if database.DB.Where("id = ?", *lightConeID).First(&lightCone).Error != nil {
+		return http.StatusNotFound, "Light cone not found"
+	}
+	return 0, ""
+}
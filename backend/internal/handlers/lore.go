@@ -0,0 +1,258 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hsr-tools/backend/internal/database"
+	"github.com/hsr-tools/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// Lore graph node types
+const (
+	LoreNodeCharacter = "character"
+	LoreNodeFaction   = "faction"
+	LoreNodeLocation  = "location"
+	LoreNodeTimeline  = "timeline"
+)
+
+const (
+	defaultNeighborDepth = 1
+	maxNeighborDepth     = 3
+)
+
+// LoreNode is a graph node. IDs are prefixed with the node type because
+// factions and locations can share an ID (e.g. "xianzhou_luofu").
+type LoreNode struct {
+	ID       string      `json:"id"`
+	Type     string      `json:"type"`
+	Label    string      `json:"label"`
+	Distance *int        `json:"distance,omitempty"` // Hops from the origin in neighbor queries
+	Data     interface{} `json:"data"`
+}
+
+// LoreEdge is a directed graph edge between two nodes
+type LoreEdge struct {
+	ID     string `json:"id"`
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Type   string `json:"type"` // Relationship type, "member", "connection", "next", "located_at" or "appears_in"
+	Label  string `json:"label,omitempty"`
+}
+
+type LoreGraph struct {
+	Nodes []LoreNode `json:"nodes"`
+	Edges []LoreEdge `json:"edges"`
+}
+
+func loreNodeID(nodeType, id string) string {
+	return nodeType + ":" + id
+}
+
+// addEdge only keeps edges whose endpoints are both part of the graph, so
+// filtered views never reference missing nodes
+func (g *LoreGraph) addEdge(nodes map[string]bool, edge LoreEdge) {
+	if nodes[edge.Source] && nodes[edge.Target] {
+		edge.ID = edge.Source + "->" + edge.Target
+		g.Edges = append(g.Edges, edge)
+	}
+}
+
+func characterLoreNode(lore models.CharacterLore) LoreNode {
+	return LoreNode{ID: loreNodeID(LoreNodeCharacter, lore.ID), Type: LoreNodeCharacter, Label: lore.Name, Data: lore}
+}
+
+// GetLoreGraph returns the lore graph as nodes and edges ready for rendering.
+// ?view=characters|factions|locations|timeline limits the graph to one view.
+func GetLoreGraph(c *gin.Context) {
+	view := c.DefaultQuery("view", "all")
+	include := func(nodeType string) bool {
+		return view == "all" || view == nodeType+"s" || view == nodeType
+	}
+	switch view {
+	case "all", "characters", "factions", "locations", "timeline":
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "view must be one of all, characters, factions, locations, timeline"})
+		return
+	}
+
+	var characters []models.CharacterLore
+	var relationships []models.CharacterRelationship
+	var factions []models.Faction
+	var locations []models.Location
+	var connections []models.LocationConnection
+	var events []models.TimelineEvent
+
+	db := database.DB
+	queries := []func() *gorm.DB{
+		func() *gorm.DB { return db.Order("name ASC").Find(&characters) },
+		func() *gorm.DB { return db.Order("id ASC").Find(&relationships) },
+		func() *gorm.DB { return db.Order("name ASC").Find(&factions) },
+		func() *gorm.DB { return db.Order("name ASC").Find(&locations) },
+		func() *gorm.DB { return db.Order("id ASC").Find(&connections) },
+		func() *gorm.DB { return db.Preload("Characters").Order("sort_order ASC").Find(&events) },
+	}
+	for _, query := range queries {
+		if err := query().Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch lore"})
+			return
+		}
+	}
+
+	graph := LoreGraph{Nodes: []LoreNode{}, Edges: []LoreEdge{}}
+	nodes := make(map[string]bool)
+	addNode := func(node LoreNode) {
+		graph.Nodes = append(graph.Nodes, node)
+		nodes[node.ID] = true
+	}
+
+	// The faction view also needs member characters
+	if include(LoreNodeCharacter) || view == "factions" {
+		for _, lore := range characters {
+			addNode(characterLoreNode(lore))
+		}
+	}
+	if include(LoreNodeFaction) {
+		for _, f := range factions {
+			addNode(LoreNode{ID: loreNodeID(LoreNodeFaction, f.ID), Type: LoreNodeFaction, Label: f.Name, Data: f})
+		}
+	}
+	if include(LoreNodeLocation) {
+		for _, l := range locations {
+			addNode(LoreNode{ID: loreNodeID(LoreNodeLocation, l.ID), Type: LoreNodeLocation, Label: l.Name, Data: l})
+		}
+	}
+	if include(LoreNodeTimeline) {
+		for _, e := range events {
+			addNode(LoreNode{ID: loreNodeID(LoreNodeTimeline, e.ID), Type: LoreNodeTimeline, Label: e.Title, Data: e})
+		}
+	}
+
+	if include(LoreNodeCharacter) {
+		for _, r := range relationships {
+			graph.addEdge(nodes, LoreEdge{
+				Source: loreNodeID(LoreNodeCharacter, r.FromID),
+				Target: loreNodeID(LoreNodeCharacter, r.ToID),
+				Type:   r.Type,
+				Label:  r.Label,
+			})
+		}
+	}
+	for _, lore := range characters {
+		if lore.FactionID != nil {
+			graph.addEdge(nodes, LoreEdge{
+				Source: loreNodeID(LoreNodeFaction, *lore.FactionID),
+				Target: loreNodeID(LoreNodeCharacter, lore.ID),
+				Type:   "member",
+			})
+		}
+	}
+	for _, conn := range connections {
+		graph.addEdge(nodes, LoreEdge{
+			Source: loreNodeID(LoreNodeLocation, conn.FromID),
+			Target: loreNodeID(LoreNodeLocation, conn.ToID),
+			Type:   "connection",
+		})
+	}
+	for i, e := range events {
+		eventID := loreNodeID(LoreNodeTimeline, e.ID)
+		if i > 0 {
+			graph.addEdge(nodes, LoreEdge{Source: loreNodeID(LoreNodeTimeline, events[i-1].ID), Target: eventID, Type: "next"})
+		}
+		if e.LocationID != nil {
+			graph.addEdge(nodes, LoreEdge{Source: eventID, Target: loreNodeID(LoreNodeLocation, *e.LocationID), Type: "located_at"})
+		}
+		for _, tc := range e.Characters {
+			graph.addEdge(nodes, LoreEdge{Source: loreNodeID(LoreNodeCharacter, tc.CharacterID), Target: eventID, Type: "appears_in"})
+		}
+	}
+
+	c.JSON(http.StatusOK, graph)
+}
+
+// GetLoreNeighbors returns the characters reachable from a character through
+// relationships in either direction, up to ?depth= hops
+func GetLoreNeighbors(c *gin.Context) {
+	id := c.Param("id")
+
+	depth := defaultNeighborDepth
+	if d := c.Query("depth"); d != "" {
+		parsed, err := strconv.Atoi(d)
+		if err != nil || parsed < 1 || parsed > maxNeighborDepth {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "depth must be between 1 and " + strconv.Itoa(maxNeighborDepth)})
+			return
+		}
+		depth = parsed
+	}
+
+	var origin models.CharacterLore
+	if err := database.DB.Where("id = ?", id).First(&origin).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Character lore not found"})
+		return
+	}
+
+	var relationships []models.CharacterRelationship
+	if err := database.DB.Order("id ASC").Find(&relationships).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch relationships"})
+		return
+	}
+
+	adjacent := make(map[string][]string)
+	for _, r := range relationships {
+		adjacent[r.FromID] = append(adjacent[r.FromID], r.ToID)
+		adjacent[r.ToID] = append(adjacent[r.ToID], r.FromID)
+	}
+
+	// Breadth-first search records the shortest distance to each character
+	distances := map[string]int{origin.ID: 0}
+	frontier := []string{origin.ID}
+	for hop := 1; hop <= depth && len(frontier) > 0; hop++ {
+		var next []string
+		for _, charID := range frontier {
+			for _, neighbor := range adjacent[charID] {
+				if _, seen := distances[neighbor]; !seen {
+					distances[neighbor] = hop
+					next = append(next, neighbor)
+				}
+			}
+		}
+		frontier = next
+	}
+
+	ids := make([]string, 0, len(distances))
+	for charID := range distances {
+		ids = append(ids, charID)
+	}
+	var characters []models.CharacterLore
+	if err := database.DB.Where("id IN ?", ids).Order("name ASC").Find(&characters).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch lore"})
+		return
+	}
+
+	graph := LoreGraph{Nodes: []LoreNode{}, Edges: []LoreEdge{}}
+	nodes := make(map[string]bool)
+	for _, lore := range characters {
+		node := characterLoreNode(lore)
+		distance := distances[lore.ID]
+		node.Distance = &distance
+		graph.Nodes = append(graph.Nodes, node)
+		nodes[node.ID] = true
+	}
+	for _, r := range relationships {
+		graph.addEdge(nodes, LoreEdge{
+			Source: loreNodeID(LoreNodeCharacter, r.FromID),
+			Target: loreNodeID(LoreNodeCharacter, r.ToID),
+			Type:   r.Type,
+			Label:  r.Label,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"characterId": origin.ID,
+		"depth":       depth,
+		"nodes":       graph.Nodes,
+		"edges":       graph.Edges,
+	})
+}
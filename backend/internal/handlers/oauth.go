@@ -0,0 +1,322 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/hsr-tools/backend/internal/database"
+	"github.com/hsr-tools/backend/internal/models"
+	"github.com/hsr-tools/backend/internal/oauth"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// OAuthStateTTL is how long a user has to finish logging in at the provider
+const OAuthStateTTL = 10 * time.Minute
+
+var errAccountLinkedElsewhere = errors.New("account is linked to another user")
+
+// safeRedirect only allows paths on the frontend, never other hosts
+func safeRedirect(redirect string) string {
+	if !strings.HasPrefix(redirect, "/") || strings.HasPrefix(redirect, "//") || strings.HasPrefix(redirect, "/\\") {
+		return ""
+	}
+	return redirect
+}
+
+// beginOAuth stores a pending authorization and returns the provider URL
+func beginOAuth(c *gin.Context, provider *oauth.Provider, userID *uuid.UUID, redirect string) (string, error) {
+	database.DB.Where("expires_at < ?", time.Now()).Delete(&models.OAuthState{})
+
+	state := models.OAuthState{
+		State:        oauth.NewState(),
+		Provider:     provider.Name,
+		CodeVerifier: oauth.NewVerifier(),
+		UserID:       userID,
+		RedirectURL:  safeRedirect(redirect),
+		ExpiresAt:    time.Now().Add(OAuthStateTTL),
+	}
+	if err := database.DB.Create(&state).Error; err != nil {
+		return "", err
+	}
+	return provider.AuthCodeURL(c.Request.Context(), state.State, state.CodeVerifier)
+}
+
+func GetOAuthProviders(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"providers": oauth.Names()})
+}
+
+// StartOAuthLogin redirects the browser to the provider's login page.
+// ?redirect=/path sends the browser back to that frontend page afterwards.
+func StartOAuthLogin(c *gin.Context) {
+	provider, err := oauth.Get(c.Param("provider"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Unknown provider"})
+		return
+	}
+
+	authURL, err := beginOAuth(c, provider, nil, c.Query("redirect"))
+	if err != nil {
+		log.Printf("Failed to start %s login: %v", provider.Name, err)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to start login with provider"})
+		return
+	}
+
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// LinkOAuthAccount starts linking a provider to the logged in user. The
+// frontend navigates to the returned URL since it can't send the bearer
+// token through a browser redirect.
+func LinkOAuthAccount(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	uid := userID.(uuid.UUID)
+
+	provider, err := oauth.Get(c.Param("provider"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Unknown provider"})
+		return
+	}
+
+	var req struct {
+		Redirect string `json:"redirect"`
+	}
+	c.ShouldBindJSON(&req)
+
+	authURL, err := beginOAuth(c, provider, &uid, req.Redirect)
+	if err != nil {
+		log.Printf("Failed to start %s link: %v", provider.Name, err)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to start login with provider"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"url": authURL})
+}
+
+// OAuthCallback finishes the flow: it exchanges the code with the PKCE
+// verifier, then logs the user in or links the account
+func OAuthCallback(c *gin.Context) {
+	provider, err := oauth.Get(c.Param("provider"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Unknown provider"})
+		return
+	}
+
+	// States are single use, so they're deleted as they're read
+	var state models.OAuthState
+	result := database.DB.Clauses(clause.Returning{}).
+		Where("state = ? AND provider = ? AND expires_at > ?", c.Query("state"), provider.Name, time.Now()).
+		Delete(&state)
+	if result.Error != nil || result.RowsAffected == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired login state"})
+		return
+	}
+
+	fail := func(status int, message string) {
+		if state.RedirectURL != "" {
+			c.Redirect(http.StatusFound, oauth.FrontendURL+state.RedirectURL+"#"+url.Values{"error": {message}}.Encode())
+			return
+		}
+		c.JSON(status, gin.H{"error": message})
+	}
+
+	if errParam := c.Query("error"); errParam != "" {
+		fail(http.StatusBadRequest, "Provider denied login: "+errParam)
+		return
+	}
+
+	ctx := c.Request.Context()
+	token, err := provider.Exchange(ctx, c.Query("code"), state.CodeVerifier)
+	if err != nil {
+		log.Printf("OAuth %s: %v", provider.Name, err)
+		fail(http.StatusBadGateway, "Failed to log in with provider")
+		return
+	}
+	info, err := provider.UserInfo(ctx, token.AccessToken)
+	if err != nil {
+		log.Printf("OAuth %s: %v", provider.Name, err)
+		fail(http.StatusBadGateway, "Failed to log in with provider")
+		return
+	}
+
+	account := models.Account{
+		Type:              "oauth",
+		Provider:          provider.Name,
+		ProviderAccountID: info.Subject,
+		Email:             info.Email,
+		AccessToken:       token.AccessToken,
+		RefreshToken:      token.RefreshToken,
+		IDToken:           token.IDToken,
+		TokenType:         token.TokenType,
+		Scope:             token.Scope,
+	}
+	if provider.IsOIDC() {
+		account.Type = "oidc"
+	}
+	if token.ExpiresIn > 0 {
+		expires := time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+		account.ExpiresAt = &expires
+	}
+
+	// Linking to the user who started the flow
+	if state.UserID != nil {
+		if err := linkAccount(database.DB, *state.UserID, &account); err != nil {
+			if errors.Is(err, errAccountLinkedElsewhere) {
+				fail(http.StatusConflict, "This account is already linked to another user")
+				return
+			}
+			fail(http.StatusInternalServerError, "Failed to link account")
+			return
+		}
+		if state.RedirectURL != "" {
+			c.Redirect(http.StatusFound, oauth.FrontendURL+state.RedirectURL+"#"+url.Values{"linked": {provider.Name}}.Encode())
+			return
+		}
+		c.JSON(http.StatusOK, account)
+		return
+	}
+
+	var user models.User
+	var tokens TokenPair
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if user, err = resolveOAuthUser(tx, info, &account); err != nil {
+			return err
+		}
+		tokens, _, err = issueTokens(tx, c, user, uuid.Nil)
+		return err
+	})
+	if err != nil {
+		log.Printf("OAuth %s login failed: %v", provider.Name, err)
+		fail(http.StatusInternalServerError, "Failed to log in")
+		return
+	}
+
+	recordActivity(user.ID, models.ActivityLogin, gin.H{"ip": c.ClientIP(), "provider": provider.Name})
+
+	if state.RedirectURL != "" {
+		fragment := url.Values{"token": {tokens.Token}, "refreshToken": {tokens.RefreshToken}}
+		c.Redirect(http.StatusFound, oauth.FrontendURL+state.RedirectURL+"#"+fragment.Encode())
+		return
+	}
+	c.JSON(http.StatusOK, AuthResponse{
+		Token:        tokens.Token,
+		RefreshToken: tokens.RefreshToken,
+		User:         user,
+	})
+}
+
+// linkAccount attaches the provider account to a user, refreshing the stored
+// provider tokens if it's already linked to them
+func linkAccount(tx *gorm.DB, userID uuid.UUID, account *models.Account) error {
+	var existing models.Account
+	err := tx.Where("provider = ? AND provider_account_id = ?", account.Provider, account.ProviderAccountID).First(&existing).Error
+	if err == nil {
+		if existing.UserID != userID {
+			return errAccountLinkedElsewhere
+		}
+		account.ID = existing.ID
+		account.UserID = userID
+		account.CreatedAt = existing.CreatedAt
+		return tx.Save(account).Error
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	account.UserID = userID
+	return tx.Create(account).Error
+}
+
+// resolveOAuthUser finds the user for a provider login. Known accounts log
+// in directly; otherwise a verified provider email is matched to an
+// existing user, and failing that a new user is created. Matching an
+// unverified user takes the account over from its password.
+func resolveOAuthUser(tx *gorm.DB, info *oauth.UserInfo, account *models.Account) (models.User, error) {
+	var user models.User
+
+	var existing models.Account
+	if tx.Where("provider = ? AND provider_account_id = ?", account.Provider, account.ProviderAccountID).First(&existing).Error == nil {
+		if err := tx.Where("id = ?", existing.UserID).First(&user).Error; err != nil {
+			return user, err
+		}
+		return user, linkAccount(tx, user.ID, account)
+	}
+
+	// Unverified provider emails could belong to anyone, so they never
+	// match an existing user
+	found := info.Email != "" && info.EmailVerified &&
+		tx.Where("email = ?", info.Email).First(&user).Error == nil
+	if !found {
+		if info.Email == "" {
+			return user, errors.New("provider did not return an email address")
+		}
+		if tx.Where("email = ?", info.Email).First(&models.User{}).Error == nil {
+			return user, errors.New("email is registered but not verified by the provider")
+		}
+		user = models.User{Email: info.Email, Name: info.Name, EmailVerified: info.EmailVerified}
+		if err := tx.Create(&user).Error; err != nil {
+			return user, err
+		}
+	} else if !user.EmailVerified {
+		// Nobody proved they own the address before, so whoever set the
+		// password may not be this user. Drop the password and end its
+		// sessions, or a squatter would share the account.
+		if err := tx.Model(&user).Updates(map[string]interface{}{"email_verified": true, "password_hash": ""}).Error; err != nil {
+			return user, err
+		}
+		if err := revokeRefreshTokens(tx.Where("user_id = ?", user.ID)); err != nil {
+			return user, err
+		}
+	}
+
+	return user, linkAccount(tx, user.ID, account)
+}
+
+func GetUserAccounts(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var accounts []models.Account
+	database.DB.Where("user_id = ?", userID).Order("created_at ASC").Find(&accounts)
+
+	c.JSON(http.StatusOK, accounts)
+}
+
+// UnlinkOAuthAccount removes a provider from the user, as long as they can
+// still log in with a password or another provider afterwards
+func UnlinkOAuthAccount(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	provider := c.Param("provider")
+
+	var user models.User
+	if err := database.DB.Where("id = ?", userID).First(&user).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	var account models.Account
+	if err := database.DB.Where("user_id = ? AND provider = ?", userID, provider).First(&account).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Account not linked"})
+		return
+	}
+
+	var linked int64
+	database.DB.Model(&models.Account{}).Where("user_id = ?", userID).Count(&linked)
+	if user.PasswordHash == "" && linked <= 1 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Set a password or link another provider before unlinking your only login method"})
+		return
+	}
+
+	if err := database.DB.Delete(&account).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unlink account"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Account unlinked"})
+}
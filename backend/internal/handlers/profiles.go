@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hsr-tools/backend/internal/mihomo"
+)
+
+// writeProfileError maps profile service errors to HTTP responses
+func writeProfileError(c *gin.Context, err error) {
+	var upstream *mihomo.UpstreamError
+	switch {
+	case errors.Is(err, mihomo.ErrInvalidUID):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "UID must be 9 digits"})
+	case errors.As(err, &upstream) && upstream.StatusCode == http.StatusNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Profile not found"})
+	case errors.As(err, &upstream) && upstream.StatusCode == http.StatusTooManyRequests:
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "Mihomo API rate limit reached, try again later"})
+	default:
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to fetch from Mihomo API"})
+	}
+}
+
+// loadShowcaseProfile fetches and parses the cached showcase for uid
+func loadShowcaseProfile(c *gin.Context, uid string) (*mihomo.ShowcaseProfile, *mihomo.Result, bool) {
+	result, err := mihomo.Profiles.Get(c.Request.Context(), uid)
+	if err != nil {
+		writeProfileError(c, err)
+		return nil, nil, false
+	}
+
+	profile, err := mihomo.ParseProfile(result.Data)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Unexpected response from Mihomo API"})
+		return nil, nil, false
+	}
+	return profile, result, true
+}
+
+func GetShowcaseProfile(c *gin.Context) {
+	profile, result, ok := loadShowcaseProfile(c, c.Param("uid"))
+	if !ok {
+		return
+	}
+
+	c.Header("X-Cache", result.Cache)
+	c.Header("Last-Modified", result.FetchedAt.UTC().Format(http.TimeFormat))
+	c.JSON(http.StatusOK, profile)
+}
@@ -0,0 +1,247 @@
+package handlers
+
+import (
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/hsr-tools/backend/internal/database"
+	"github.com/hsr-tools/backend/internal/engine"
+	"github.com/hsr-tools/backend/internal/models"
+)
+
+type PullPlanRequest struct {
+	Name         *string `json:"name" binding:"omitempty,max=100"`
+	BannerType   *string `json:"bannerType" binding:"omitempty,oneof=standard lightCone"`
+	BannerID     *int    `json:"bannerId"`
+	CurrentPity  *int    `json:"currentPity" binding:"omitempty,min=0,max=89"`
+	IsGuaranteed *bool   `json:"isGuaranteed"`
+	StellarJade  *int    `json:"stellarJade" binding:"omitempty,min=0"`
+	Passes       *int    `json:"passes" binding:"omitempty,min=0"`
+	DailyIncome  *int    `json:"dailyIncome" binding:"omitempty,min=0"`
+}
+
+type PullPlanProjection struct {
+	PullsAvailable        int        `json:"pullsAvailable"`
+	ExpectedPullsNeeded   int        `json:"expectedPullsNeeded"`
+	StellarJadeNeeded     int        `json:"stellarJadeNeeded"`
+	DailyIncome           int        `json:"dailyIncome"`
+	DaysUntilEnough       int        `json:"daysUntilEnough"`
+	ReadyDate             time.Time  `json:"readyDate"`
+	ReadyBeforeBannerEnds *bool      `json:"readyBeforeBannerEnds,omitempty"`
+	BannerEndDate         *time.Time `json:"bannerEndDate,omitempty"`
+}
+
+type PullPlanResponse struct {
+	models.PullPlan
+	Projection *PullPlanProjection `json:"projection"`
+}
+
+// projectPullPlan computes how long until the plan can afford the
+// expected number of pulls for its banner
+func projectPullPlan(plan models.PullPlan) *PullPlanProjection {
+	rates, ok := engine.GetPityRates(plan.BannerType)
+	if !ok {
+		return nil
+	}
+	expected, err := engine.ExpectedPulls(plan.BannerType, plan.CurrentPity, plan.IsGuaranteed)
+	if err != nil {
+		return nil
+	}
+
+	dailyIncome := plan.DailyIncome
+	if dailyIncome <= 0 {
+		dailyIncome = engine.DailyStellarJadeIncome
+	}
+
+	pullsNeeded := int(math.Ceil(expected))
+	pullsAvailable := plan.StellarJade/rates.CostPerPull + plan.Passes
+	days := engine.DaysUntilEnough(plan.StellarJade, plan.Passes, pullsNeeded, rates.CostPerPull, dailyIncome)
+	readyDate := time.Now().AddDate(0, 0, days).Truncate(24 * time.Hour)
+
+	projection := &PullPlanProjection{
+		PullsAvailable:      pullsAvailable,
+		ExpectedPullsNeeded: pullsNeeded,
+		StellarJadeNeeded:   max(0, (pullsNeeded-pullsAvailable)*rates.CostPerPull),
+		DailyIncome:         dailyIncome,
+		DaysUntilEnough:     days,
+		ReadyDate:           readyDate,
+	}
+
+	if plan.Banner != nil {
+		ready := !readyDate.After(plan.Banner.EndDate)
+		projection.ReadyBeforeBannerEnds = &ready
+		projection.BannerEndDate = &plan.Banner.EndDate
+	}
+
+	return projection
+}
+
+func toPullPlanResponse(plan models.PullPlan) PullPlanResponse {
+	return PullPlanResponse{PullPlan: plan, Projection: projectPullPlan(plan)}
+}
+
+// applyPullPlanRequest copies the fields set in req onto plan
+func applyPullPlanRequest(plan *models.PullPlan, req PullPlanRequest) {
+	if req.Name != nil {
+		plan.Name = *req.Name
+	}
+	if req.BannerType != nil {
+		plan.BannerType = *req.BannerType
+	}
+	if req.BannerID != nil {
+		plan.BannerID = req.BannerID
+		if *req.BannerID == 0 {
+			plan.BannerID = nil
+		}
+	}
+	if req.CurrentPity != nil {
+		plan.CurrentPity = *req.CurrentPity
+	}
+	if req.IsGuaranteed != nil {
+		plan.IsGuaranteed = *req.IsGuaranteed
+	}
+	if req.StellarJade != nil {
+		plan.StellarJade = *req.StellarJade
+	}
+	if req.Passes != nil {
+		plan.Passes = *req.Passes
+	}
+	if req.DailyIncome != nil {
+		plan.DailyIncome = *req.DailyIncome
+	}
+}
+
+// validatePullPlan checks the plan against its banner type and linked banner
+func validatePullPlan(plan *models.PullPlan) (int, string) {
+	rates, ok := engine.GetPityRates(plan.BannerType)
+	if !ok {
+		return http.StatusBadRequest, "Invalid banner type"
+	}
+	if plan.CurrentPity >= rates.HardPity {
+		return http.StatusBadRequest, "Current pity exceeds hard pity for this banner type"
+	}
+
+	plan.Banner = nil
+	if plan.BannerID != nil {
+		var banner models.Banner
+		if database.DB.Where("id = ?", *plan.BannerID).First(&banner).Error != nil {
+			return http.StatusNotFound, "Banner not found"
+		}
+		plan.Banner = &banner
+	}
+	return 0, ""
+}
+
+func GetPullPlans(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var plans []models.PullPlan
+	if err := database.DB.Preload("Banner").Where("user_id = ?", userID).
+		Order("updated_at DESC").Find(&plans).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch pull plans"})
+		return
+	}
+
+	response := make([]PullPlanResponse, len(plans))
+	for i, plan := range plans {
+		response[i] = toPullPlanResponse(plan)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func GetPullPlan(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var plan models.PullPlan
+	if err := database.DB.Preload("Banner").
+		Where("id = ? AND user_id = ?", c.Param("id"), userID).First(&plan).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Pull plan not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, toPullPlanResponse(plan))
+}
+
+func CreatePullPlan(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var req PullPlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	plan := models.PullPlan{
+		UserID:     userID.(uuid.UUID),
+		BannerType: engine.BannerStandard,
+	}
+	applyPullPlanRequest(&plan, req)
+
+	if status, msg := validatePullPlan(&plan); status != 0 {
+		c.JSON(status, gin.H{"error": msg})
+		return
+	}
+
+	if err := database.DB.Omit("Banner").Create(&plan).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create pull plan"})
+		return
+	}
+
+	recordActivity(plan.UserID, models.ActivityPullPlanner, gin.H{"planId": plan.ID, "action": "create"})
+
+	c.JSON(http.StatusCreated, toPullPlanResponse(plan))
+}
+
+func UpdatePullPlan(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var req PullPlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var plan models.PullPlan
+	if err := database.DB.Where("id = ? AND user_id = ?", c.Param("id"), userID).First(&plan).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Pull plan not found"})
+		return
+	}
+
+	applyPullPlanRequest(&plan, req)
+
+	if status, msg := validatePullPlan(&plan); status != 0 {
+		c.JSON(status, gin.H{"error": msg})
+		return
+	}
+
+	if err := database.DB.Omit("Banner").Save(&plan).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update pull plan"})
+		return
+	}
+
+	recordActivity(plan.UserID, models.ActivityPullPlanner, gin.H{"planId": plan.ID, "action": "update"})
+
+	c.JSON(http.StatusOK, toPullPlanResponse(plan))
+}
+
+func DeletePullPlan(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	result := database.DB.Where("id = ? AND user_id = ?", c.Param("id"), userID).
+		Delete(&models.PullPlan{})
+
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete pull plan"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Pull plan not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Deleted successfully"})
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hsr-tools/backend/internal/engine"
+)
+
+type PullSimulateRequest struct {
+	BannerType   string `json:"bannerType" binding:"omitempty,oneof=standard lightCone"`
+	CurrentPity  int    `json:"currentPity" binding:"min=0"`
+	IsGuaranteed bool   `json:"isGuaranteed"`
+	Pulls        int    `json:"pulls" binding:"min=0,max=2000"`
+	Iterations   int    `json:"iterations" binding:"omitempty,min=1,max=2000000"`
+	Seed         *int64 `json:"seed"`
+}
+
+func SimulatePulls(c *gin.Context) {
+	var req PullSimulateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	bannerType := req.BannerType
+	if bannerType == "" {
+		bannerType = engine.BannerStandard
+	}
+
+	seed := time.Now().UnixNano()
+	if req.Seed != nil {
+		seed = *req.Seed
+	}
+
+	result, err := engine.SimulatePulls(engine.PullSimulation{
+		BannerType:   bannerType,
+		CurrentPity:  req.CurrentPity,
+		IsGuaranteed: req.IsGuaranteed,
+		Pulls:        req.Pulls,
+		Iterations:   req.Iterations,
+		Seed:         seed,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"math"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hsr-tools/backend/internal/database"
+	"github.com/hsr-tools/backend/internal/engine"
+	"github.com/hsr-tools/backend/internal/mihomo"
+	"github.com/hsr-tools/backend/internal/models"
+)
+
+type RelicScoreRequest struct {
+	CharacterID string            `json:"characterId" binding:"required"`
+	Relic       engine.RelicInput `json:"relic"`
+}
+
+type ScoredRelic struct {
+	mihomo.ShowcaseRelic
+	Rating engine.RelicScore `json:"rating"`
+}
+
+type CharacterRelicScores struct {
+	CharID      string        `json:"charId"`
+	CharacterID string        `json:"characterId,omitempty"`
+	Name        string        `json:"name"`
+	DefaultUsed bool          `json:"defaultBuild"`
+	Score       int           `json:"score"`
+	Grade       string        `json:"grade"`
+	Relics      []ScoredRelic `json:"relics"`
+}
+
+type ProfileRelicScoresResponse struct {
+	UID        string                 `json:"uid"`
+	Characters []CharacterRelicScores `json:"characters"`
+}
+
+// buildWeightsFor returns the seeded build weights for a character, or the
+// default weights when it has none
+func buildWeightsFor(char models.Character) (engine.BuildWeights, bool) {
+	if char.Build == nil || len(char.Build.Substats) == 0 {
+		return engine.DefaultBuildWeights(), true
+	}
+	return engine.WeightsFromBuild(*char.Build), false
+}
+
+func ScoreRelic(c *gin.Context) {
+	var req RelicScoreRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var character models.Character
+	if err := database.DB.Preload("Build.Substats").
+		Where("id = ?", req.CharacterID).First(&character).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Character not found"})
+		return
+	}
+
+	weights, defaultUsed := buildWeightsFor(character)
+	rating := engine.ScoreRelic(req.Relic, weights)
+
+	c.JSON(http.StatusOK, gin.H{
+		"characterId":  character.ID,
+		"defaultBuild": defaultUsed,
+		"rating":       rating,
+	})
+}
+
+func GetProfileRelicScores(c *gin.Context) {
+	profile, result, ok := loadShowcaseProfile(c, c.Param("uid"))
+	if !ok {
+		return
+	}
+
+	charIDs := make([]string, len(profile.Characters))
+	for i, sc := range profile.Characters {
+		charIDs[i] = sc.ID
+	}
+	var characters []models.Character
+	if err := database.DB.Preload("Build.Substats").Where("char_id IN ?", charIDs).Find(&characters).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch builds"})
+		return
+	}
+	byCharID := make(map[string]models.Character, len(characters))
+	for _, char := range characters {
+		byCharID[char.CharID] = char
+	}
+
+	response := ProfileRelicScoresResponse{
+		UID:        profile.UID,
+		Characters: make([]CharacterRelicScores, 0, len(profile.Characters)),
+	}
+
+	for _, sc := range profile.Characters {
+		weights := engine.DefaultBuildWeights()
+		scores := CharacterRelicScores{
+			CharID:      sc.ID,
+			Name:        sc.Name,
+			DefaultUsed: true,
+			Relics:      make([]ScoredRelic, 0, len(sc.Relics)),
+		}
+		if char, ok := byCharID[sc.ID]; ok {
+			scores.CharacterID = char.ID
+			weights, scores.DefaultUsed = buildWeightsFor(char)
+		}
+
+		total := 0
+		for _, relic := range sc.Relics {
+			input := engine.RelicInput{
+				Slot:     relic.Slot,
+				MainStat: relic.MainStat.Type,
+				Substats: make([]engine.RelicSubstat, len(relic.Substats)),
+			}
+			for i, sub := range relic.Substats {
+				input.Substats[i] = engine.RelicSubstat{Type: sub.Type, Value: sub.Value, Rolls: sub.Rolls}
+			}
+
+			rating := engine.ScoreRelic(input, weights)
+			total += rating.Score
+			scores.Relics = append(scores.Relics, ScoredRelic{ShowcaseRelic: relic, Rating: rating})
+		}
+
+		if len(sc.Relics) > 0 {
+			scores.Score = int(math.Round(float64(total) / float64(len(sc.Relics))))
+		}
+		scores.Grade = engine.Grade(scores.Score)
+		response.Characters = append(response.Characters, scores)
+	}
+
+	c.Header("X-Cache", result.Cache)
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/hsr-tools/backend/internal/database"
+	"github.com/hsr-tools/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type ImportedCharacter struct {
+	CharacterID string `json:"characterId"`
+	Name        string `json:"name"`
+	Eidolon     int    `json:"eidolon"`
+	Level       int    `json:"level"`
+}
+
+type UnmatchedCharacter struct {
+	CharID string `json:"charId"`
+	Name   string `json:"name"`
+}
+
+type RosterImportResponse struct {
+	UID       string               `json:"uid"`
+	Added     []ImportedCharacter  `json:"added"`
+	Updated   []ImportedCharacter  `json:"updated"`
+	Unchanged []ImportedCharacter  `json:"unchanged"`
+	Unmatched []UnmatchedCharacter `json:"unmatched"`
+}
+
+func ImportUserCharacters(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	uid := userID.(uuid.UUID)
+
+	var user models.User
+	if err := database.DB.Where("id = ?", uid).First(&user).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	if user.UID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Set your UID before importing characters"})
+		return
+	}
+
+	profile, _, ok := loadShowcaseProfile(c, user.UID)
+	if !ok {
+		return
+	}
+
+	// Map showcase IDs (game IDs) to our characters
+	charIDs := make([]string, len(profile.Characters))
+	for i, sc := range profile.Characters {
+		charIDs[i] = sc.ID
+	}
+	var characters []models.Character
+	if err := database.DB.Where("char_id IN ?", charIDs).Find(&characters).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to import characters"})
+		return
+	}
+	byCharID := make(map[string]models.Character, len(characters))
+	for _, char := range characters {
+		byCharID[char.CharID] = char
+	}
+
+	response := RosterImportResponse{
+		UID:       user.UID,
+		Added:     []ImportedCharacter{},
+		Updated:   []ImportedCharacter{},
+		Unchanged: []ImportedCharacter{},
+		Unmatched: []UnmatchedCharacter{},
+	}
+
+	// All or nothing, so a failure halfway doesn't leave a partial import
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		for _, sc := range profile.Characters {
+			char, ok := byCharID[sc.ID]
+			if !ok {
+				response.Unmatched = append(response.Unmatched, UnmatchedCharacter{CharID: sc.ID, Name: sc.Name})
+				continue
+			}
+
+			sc.Eidolon = max(0, min(models.MaxEidolon, sc.Eidolon))
+
+			imported := ImportedCharacter{
+				CharacterID: char.ID,
+				Name:        char.Name,
+				Eidolon:     sc.Eidolon,
+				Level:       sc.Level,
+			}
+
+			var existing models.UserCharacter
+			err := tx.Where("user_id = ? AND character_id = ?", uid, char.ID).First(&existing).Error
+			switch {
+			case errors.Is(err, gorm.ErrRecordNotFound):
+				userChar := models.UserCharacter{
+					UserID:      uid,
+					CharacterID: char.ID,
+					Eidolon:     sc.Eidolon,
+					Level:       sc.Level,
+				}
+				if err := tx.Create(&userChar).Error; err != nil {
+					return err
+				}
+				response.Added = append(response.Added, imported)
+
+			case err != nil:
+				return err
+
+			case existing.Eidolon != sc.Eidolon || existing.Level != sc.Level:
+				if err := tx.Model(&existing).
+					Updates(map[string]interface{}{"eidolon": sc.Eidolon, "level": sc.Level}).Error; err != nil {
+					return err
+				}
+				response.Updated = append(response.Updated, imported)
+
+			default:
+				response.Unchanged = append(response.Unchanged, imported)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to import characters"})
+		return
+	}
+
+	recordActivity(uid, models.ActivityRosterImport, gin.H{
+		"uid":       user.UID,
+		"added":     len(response.Added),
+		"updated":   len(response.Updated),
+		"unmatched": len(response.Unmatched),
+	})
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/hsr-tools/backend/internal/database"
+	"github.com/hsr-tools/backend/internal/models"
+	"github.com/hsr-tools/backend/pkg/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	errRefreshInvalid = errors.New("invalid refresh token")
+	errRefreshReused  = errors.New("refresh token reuse detected")
+)
+
+type TokenPair struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refreshToken"`
+}
+
+// issueTokens creates an access token and a persisted refresh token. Pass
+// uuid.Nil as familyID to start a new session.
+func issueTokens(tx *gorm.DB, c *gin.Context, user models.User, familyID uuid.UUID) (TokenPair, *models.RefreshToken, error) {
+	token, err := utils.GenerateToken(user.ID, user.Email)
+	if err != nil {
+		return TokenPair{}, nil, err
+	}
+	refreshToken, claims, err := utils.GenerateRefreshToken(user.ID, user.Email)
+	if err != nil {
+		return TokenPair{}, nil, err
+	}
+
+	if familyID == uuid.Nil {
+		familyID = uuid.New()
+	}
+	stored := models.RefreshToken{
+		ID:        uuid.MustParse(claims.ID),
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		UserAgent: truncate(c.Request.UserAgent(), 255),
+		IP:        c.ClientIP(),
+		ExpiresAt: claims.ExpiresAt.Time,
+	}
+	if err := tx.Create(&stored).Error; err != nil {
+		return TokenPair{}, nil, err
+	}
+
+	return TokenPair{Token: token, RefreshToken: refreshToken}, &stored, nil
+}
+
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
+
+// rotateRefreshToken exchanges a refresh token for a new pair. Presenting a
+// token that was already rotated means it leaked, so its whole family is
+// revoked.
+func rotateRefreshToken(c *gin.Context, refreshToken string) (TokenPair, error) {
+	claims, err := utils.ValidateRefreshToken(refreshToken)
+	if err != nil {
+		return TokenPair{}, errRefreshInvalid
+	}
+	jti, err := uuid.Parse(claims.ID)
+	if err != nil {
+		return TokenPair{}, errRefreshInvalid
+	}
+
+	var pair TokenPair
+	var reused *models.RefreshToken
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		var stored models.RefreshToken
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND user_id = ?", jti, claims.UserID).First(&stored).Error; err != nil {
+			return errRefreshInvalid
+		}
+		if stored.RevokedAt != nil {
+			if stored.ReplacedBy != nil {
+				reused = &stored
+				return errRefreshReused
+			}
+			return errRefreshInvalid
+		}
+
+		var user models.User
+		if err := tx.Where("id = ?", stored.UserID).First(&user).Error; err != nil {
+			return errRefreshInvalid
+		}
+
+		var next *models.RefreshToken
+		pair, next, err = issueTokens(tx, c, user, stored.FamilyID)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		return tx.Model(&stored).Updates(map[string]interface{}{"revoked_at": now, "replaced_by": next.ID}).Error
+	})
+
+	// Revoke outside the rolled back transaction
+	if reused != nil {
+		log.Printf("⚠ Refresh token reuse for user %s, revoking session family %s", reused.UserID, reused.FamilyID)
+		revokeRefreshTokens(database.DB.Where("family_id = ?", reused.FamilyID))
+	}
+	return pair, err
+}
+
+// revokeRefreshTokens revokes every active refresh token matched by query
+func revokeRefreshTokens(query *gorm.DB) error {
+	return query.Model(&models.RefreshToken{}).Where("revoked_at IS NULL").Update("revoked_at", time.Now()).Error
+}
+
+// Logout revokes the session the refresh token belongs to, including
+// tokens rotated from it
+func Logout(c *gin.Context) {
+	var req struct {
+		RefreshToken string `json:"refreshToken" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	claims, err := utils.ValidateRefreshToken(req.RefreshToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid refresh token"})
+		return
+	}
+
+	var stored models.RefreshToken
+	if database.DB.Where("id = ? AND user_id = ?", claims.ID, claims.UserID).First(&stored).Error == nil {
+		if err := revokeRefreshTokens(database.DB.Where("family_id = ?", stored.FamilyID)); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to log out"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out"})
+}
+
+// LogoutAll revokes every refresh token the user has, logging out all devices
+// once their access tokens expire
+func LogoutAll(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	result := database.DB.Model(&models.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to log out"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out of all devices", "revoked": result.RowsAffected})
+}
@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/hsr-tools/backend/internal/database"
+	"github.com/hsr-tools/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// MaxTeamSize is the number of characters allowed in a team
+const MaxTeamSize = 4
+
+type TeamPresetMemberRequest struct {
+	CharacterID  string  `json:"characterId" binding:"required"`
+	SpeedBonus   int     `json:"speedBonus" binding:"min=0,max=400"`
+	SpeedPercent float64 `json:"speedPercent" binding:"min=0,max=500"`
+}
+
+type TeamPresetRequest struct {
+	Name      string                    `json:"name" binding:"required,max=100"`
+	BossSpeed int                       `json:"bossSpeed" binding:"min=0"`
+	Members   []TeamPresetMemberRequest `json:"members" binding:"required,min=1,dive"`
+}
+
+// buildPresetMembers validates team size and that every character exists
+func buildPresetMembers(members []TeamPresetMemberRequest) ([]models.TeamPresetMember, int, string) {
+	if len(members) > MaxTeamSize {
+		return nil, http.StatusBadRequest, "A team can have at most 4 characters"
+	}
+
+	ids := make([]string, 0, len(members))
+	seen := make(map[string]bool)
+	for _, m := range members {
+		if seen[m.CharacterID] {
+			return nil, http.StatusBadRequest, "Duplicate character in team: " + m.CharacterID
+		}
+		seen[m.CharacterID] = true
+		ids = append(ids, m.CharacterID)
+	}
+
+	var count int64
+	database.DB.Model(&models.Character{}).Where("id IN ?", ids).Count(&count)
+	if int(count) != len(ids) {
+		return nil, http.StatusNotFound, "One or more characters not found"
+	}
+
+	result := make([]models.TeamPresetMember, len(members))
+	for i, m := range members {
+		result[i] = models.TeamPresetMember{
+			Slot:         i + 1,
+			CharacterID:  m.CharacterID,
+			SpeedBonus:   m.SpeedBonus,
+			SpeedPercent: m.SpeedPercent,
+		}
+	}
+	return result, 0, ""
+}
+
+func preloadPresetMembers(db *gorm.DB) *gorm.DB {
+	return db.Order("slot ASC")
+}
+
+func loadTeamPreset(id string, userID interface{}) (models.TeamPreset, error) {
+	var preset models.TeamPreset
+	err := database.DB.
+		Preload("Members", preloadPresetMembers).
+		Preload("Members.Character.Element").
+		Preload("Members.Character.Path").
+		Where("id = ? AND user_id = ?", id, userID).
+		First(&preset).Error
+	return preset, err
+}
+
+func GetTeamPresets(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var presets []models.TeamPreset
+	if err := database.DB.
+		Preload("Members", preloadPresetMembers).
+		Preload("Members.Character.Element").
+		Preload("Members.Character.Path").
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&presets).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch team presets"})
+		return
+	}
+
+	c.JSON(http.StatusOK, presets)
+}
+
+func GetTeamPreset(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	preset, err := loadTeamPreset(c.Param("id"), userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Team preset not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, preset)
+}
+
+func CreateTeamPreset(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var req TeamPresetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	members, status, msg := buildPresetMembers(req.Members)
+	if status != 0 {
+		c.JSON(status, gin.H{"error": msg})
+		return
+	}
+
+	preset := models.TeamPreset{
+		UserID:    userID.(uuid.UUID),
+		Name:      req.Name,
+		BossSpeed: req.BossSpeed,
+		Members:   members,
+	}
+
+	if err := database.DB.Create(&preset).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create team preset"})
+		return
+	}
+
+	recordActivity(preset.UserID, models.ActivitySpeedTuner, gin.H{"presetId": preset.ID, "name": preset.Name})
+
+	preset, _ = loadTeamPreset(preset.ID.String(), userID)
+	c.JSON(http.StatusCreated, preset)
+}
+
+func UpdateTeamPreset(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var req TeamPresetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var preset models.TeamPreset
+	if err := database.DB.Where("id = ? AND user_id = ?", c.Param("id"), userID).First(&preset).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Team preset not found"})
+		return
+	}
+
+	members, status, msg := buildPresetMembers(req.Members)
+	if status != 0 {
+		c.JSON(status, gin.H{"error": msg})
+		return
+	}
+
+	// Replace members and update the preset atomically
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("preset_id = ?", preset.ID).Delete(&models.TeamPresetMember{}).Error; err != nil {
+			return err
+		}
+		for i := range members {
+			members[i].PresetID = preset.ID
+		}
+		if err := tx.Create(&members).Error; err != nil {
+			return err
+		}
+		return tx.Model(&preset).Updates(map[string]interface{}{
+			"name":       req.Name,
+			"boss_speed": req.BossSpeed,
+		}).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update team preset"})
+		return
+	}
+
+	preset, _ = loadTeamPreset(preset.ID.String(), userID)
+	c.JSON(http.StatusOK, preset)
+}
+
+func DeleteTeamPreset(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	result := database.DB.Where("id = ? AND user_id = ?", c.Param("id"), userID).
+		Delete(&models.TeamPreset{})
+
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Team preset not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Deleted successfully"})
+}
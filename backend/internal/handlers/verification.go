@@ -0,0 +1,306 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hsr-tools/backend/internal/database"
+	"github.com/hsr-tools/backend/internal/mailer"
+	"github.com/hsr-tools/backend/internal/models"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// How long each verification token type stays valid
+var tokenLifetimes = map[string]time.Duration{
+	models.TokenTypeEmail:         24 * time.Hour,
+	models.TokenTypePasswordReset: time.Hour,
+	models.TokenTypeOTP:           10 * time.Minute,
+}
+
+// MaxOTPAttempts is the number of wrong codes allowed before an OTP is burned
+const MaxOTPAttempts = 5
+
+// ResendCooldown is how long a user waits between verification or reset
+// emails, so the endpoints can't be used to flood an inbox
+const ResendCooldown = time.Minute
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// OTPs are short, so they're hashed together with the email to keep
+// hashes unique across users
+func hashOTP(email, code string) string {
+	return hashToken(email + ":" + code)
+}
+
+// issueVerificationToken replaces any outstanding token of the same type
+// for the user and returns the new plain-text token
+func issueVerificationToken(user models.User, tokenType string) (string, error) {
+	var plain, hash string
+	if tokenType == models.TokenTypeOTP {
+		n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+		if err != nil {
+			return "", err
+		}
+		plain = fmt.Sprintf("%06d", n.Int64())
+		hash = hashOTP(user.Email, plain)
+	} else {
+		buf := make([]byte, 32)
+		if _, err := rand.Read(buf); err != nil {
+			return "", err
+		}
+		plain = hex.EncodeToString(buf)
+		hash = hashToken(plain)
+	}
+
+	token := models.VerificationToken{
+		UserID:     user.ID,
+		Identifier: user.Email,
+		TokenHash:  hash,
+		Type:       tokenType,
+		ExpiresAt:  time.Now().Add(tokenLifetimes[tokenType]),
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		// Failed guesses carry over to the new code while the old one is
+		// live, so asking for another code doesn't reset the limit
+		if tokenType == models.TokenTypeOTP {
+			var previous models.VerificationToken
+			if tx.Where("user_id = ? AND type = ? AND expires_at > ?", user.ID, tokenType, time.Now()).
+				First(&previous).Error == nil {
+				token.Attempts = previous.Attempts
+			}
+		}
+		if err := tx.Where("user_id = ? AND type = ?", user.ID, tokenType).
+			Delete(&models.VerificationToken{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&token).Error
+	})
+	if err != nil {
+		return "", err
+	}
+	return plain, nil
+}
+
+// recentlySent reports whether a token of the type was issued to the user
+// within ResendCooldown
+func recentlySent(user models.User, tokenType string) bool {
+	var count int64
+	database.DB.Model(&models.VerificationToken{}).
+		Where("user_id = ? AND type = ? AND created_at > ?", user.ID, tokenType, time.Now().Add(-ResendCooldown)).
+		Count(&count)
+	return count > 0
+}
+
+// sendVerificationEmail mails a verification link along with a code that
+// can be typed in instead. The mail is queued; failures are logged.
+func sendVerificationEmail(user models.User) {
+	link, err := issueVerificationToken(user, models.TokenTypeEmail)
+	if err != nil {
+		log.Printf("Failed to issue verification token for %s: %v", user.Email, err)
+		return
+	}
+	code, err := issueVerificationToken(user, models.TokenTypeOTP)
+	if err != nil {
+		log.Printf("Failed to issue verification code for %s: %v", user.Email, err)
+		return
+	}
+
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your HSR Tools account",
+		Body: fmt.Sprintf("Hi %s,\n\nConfirm your email address by opening this link:\n%s\n\nOr enter this code: %s\n\nThe code expires in 10 minutes and the link in 24 hours.\n",
+			user.Name, mailer.Link("/auth/verify", url.Values{"token": {link}}), code),
+	}
+	mailer.Enqueue(msg)
+}
+
+func markEmailVerified(user models.User) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&user).Update("email_verified", true).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ? AND type IN ?", user.ID, []string{models.TokenTypeEmail, models.TokenTypeOTP}).
+			Delete(&models.VerificationToken{}).Error
+	})
+}
+
+// VerifyEmail accepts either a link token or an email and OTP code
+func VerifyEmail(c *gin.Context) {
+	var req struct {
+		Token string `json:"token"`
+		Email string `json:"email" binding:"omitempty,email"`
+		OTP   string `json:"otp"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var token models.VerificationToken
+	switch {
+	case req.Token != "":
+		if err := database.DB.Where("token_hash = ? AND type = ? AND expires_at > ?",
+			hashToken(req.Token), models.TokenTypeEmail, time.Now()).First(&token).Error; err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired token"})
+			return
+		}
+	case req.Email != "" && req.OTP != "":
+		if err := database.DB.Where("identifier = ? AND type = ? AND expires_at > ?",
+			req.Email, models.TokenTypeOTP, time.Now()).First(&token).Error; err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired code"})
+			return
+		}
+		// Burned codes are kept until they expire, for resends to inherit
+		if token.Attempts >= MaxOTPAttempts ||
+			subtle.ConstantTimeCompare([]byte(token.TokenHash), []byte(hashOTP(req.Email, req.OTP))) != 1 {
+			database.DB.Model(&token).Update("attempts", gorm.Expr("attempts + 1"))
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired code"})
+			return
+		}
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Token or email and OTP are required"})
+		return
+	}
+
+	var user models.User
+	if err := database.DB.Where("id = ?", token.UserID).First(&user).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	// The token is only good for the address it was sent to
+	if user.Email != token.Identifier {
+		database.DB.Delete(&token)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired token"})
+		return
+	}
+
+	if err := markEmailVerified(user); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify email"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Email verified successfully"})
+}
+
+// ResendVerification sends a new verification email, at most once per
+// ResendCooldown. It always succeeds so it can't be used to find out which
+// addresses are registered.
+func ResendVerification(c *gin.Context) {
+	var req struct {
+		Email string `json:"email" binding:"required,email"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var user models.User
+	if database.DB.Where("email = ?", req.Email).First(&user).Error == nil && !user.EmailVerified &&
+		!recentlySent(user, models.TokenTypeEmail) {
+		sendVerificationEmail(user)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "If the account exists and is unverified, a verification email has been sent"})
+}
+
+// ForgotPassword mails a password reset link. Like ResendVerification, the
+// response doesn't reveal whether the email is registered.
+func ForgotPassword(c *gin.Context) {
+	var req struct {
+		Email string `json:"email" binding:"required,email"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var user models.User
+	if database.DB.Where("email = ?", req.Email).First(&user).Error == nil &&
+		!recentlySent(user, models.TokenTypePasswordReset) {
+		token, err := issueVerificationToken(user, models.TokenTypePasswordReset)
+		if err != nil {
+			log.Printf("Failed to issue password reset token for %s: %v", user.Email, err)
+		} else {
+			msg := mailer.Message{
+				To:      user.Email,
+				Subject: "Reset your HSR Tools password",
+				Body: fmt.Sprintf("Hi %s,\n\nReset your password by opening this link:\n%s\n\nThe link expires in 1 hour. If you didn't ask for a reset, you can ignore this email.\n",
+					user.Name, mailer.Link("/auth/reset-password", url.Values{"token": {token}})),
+			}
+			mailer.Enqueue(msg)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "If the account exists, a password reset email has been sent"})
+}
+
+func ResetPassword(c *gin.Context) {
+	var req struct {
+		Token    string `json:"token" binding:"required"`
+		Password string `json:"password" binding:"required,min=8"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var token models.VerificationToken
+	if err := database.DB.Where("token_hash = ? AND type = ? AND expires_at > ?",
+		hashToken(req.Token), models.TokenTypePasswordReset, time.Now()).First(&token).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired token"})
+		return
+	}
+
+	var user models.User
+	if err := database.DB.Where("id = ?", token.UserID).First(&user).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired token"})
+		return
+	}
+
+	// The token is only good for the address it was sent to
+	if user.Email != token.Identifier {
+		database.DB.Delete(&token)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired token"})
+		return
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to hash password"})
+		return
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.User{}).Where("id = ?", token.UserID).
+			Update("password_hash", string(hashedPassword)).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ? AND type = ?", token.UserID, models.TokenTypePasswordReset).
+			Delete(&models.VerificationToken{}).Error; err != nil {
+			return err
+		}
+		// Sessions started with the old password end here
+		return revokeRefreshTokens(tx.Where("user_id = ?", token.UserID))
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reset password"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password reset successfully"})
+}
@@ -0,0 +1,60 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/hsr-tools/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// ExpireCodes deactivates active codes whose expiry has passed and returns
+// how many were flipped
+func ExpireCodes(db *gorm.DB) (int64, error) {
+	result := db.Model(&models.Code{}).
+		Where("is_active = ? AND expires_at IS NOT NULL AND expires_at <= ?", true, time.Now()).
+		Update("is_active", false)
+	return result.RowsAffected, result.Error
+}
+
+// StartCodeExpiry runs ExpireCodes immediately and then every interval until
+// ctx is cancelled. The returned channel is closed once the job has stopped.
+// An interval of zero or less disables the job.
+func StartCodeExpiry(ctx context.Context, db *gorm.DB, interval time.Duration) <-chan struct{} {
+	done := make(chan struct{})
+	if interval <= 0 {
+		log.Println("⏰ Code expiry job disabled")
+		close(done)
+		return done
+	}
+	run := func() {
+		expired, err := ExpireCodes(db.WithContext(ctx))
+		if ctx.Err() != nil {
+			return // Cancelled part way, e.g. on shutdown
+		}
+		if err != nil {
+			log.Printf("Warning: code expiry failed: %v", err)
+			return
+		}
+		if expired > 0 {
+			log.Printf("⏰ Expired %d redemption codes", expired)
+		}
+	}
+
+	go func() {
+		defer close(done)
+		run()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				run()
+			}
+		}
+	}()
+	return done
+}
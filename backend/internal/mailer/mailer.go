@@ -0,0 +1,228 @@
+package mailer
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log"
+	"net"
+	"net/smtp"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hsr-tools/backend/internal/config"
+)
+
+// Message is a plain-text email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers outgoing email
+type Mailer interface {
+	Send(msg Message) error
+}
+
+// Default is the shared mailer, set up by Setup
+var Default Mailer = LogMailer{}
+
+// BaseURL is the frontend URL that links in emails point to
+var BaseURL = "http://localhost:3000"
+
+// queueSize bounds the messages waiting for the background sender. Past
+// it, messages are dropped and logged rather than holding up requests.
+const queueSize = 100
+
+var (
+	queue chan Message
+	done  chan struct{}
+)
+
+// Setup picks the mailer implementation from the config and starts the
+// background sender
+func Setup(cfg *config.Config) {
+	BaseURL = strings.TrimSuffix(cfg.FrontendURL, "/")
+	switch cfg.MailDriver {
+	case "smtp":
+		Default = NewSMTPMailer(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.MailFrom, cfg.SMTPTimeout)
+	case "file":
+		Default = FileMailer{Dir: cfg.MailFilePath, From: cfg.MailFrom}
+	default:
+		Default = LogMailer{}
+	}
+
+	queue = make(chan Message, queueSize)
+	done = make(chan struct{})
+	go sendQueued(queue, done)
+}
+
+// Enqueue hands a message to the background sender, so requests don't
+// wait on the mail server. Failures are logged. Before Setup, messages are
+// sent straight away.
+func Enqueue(msg Message) {
+	if queue == nil {
+		if err := Default.Send(msg); err != nil {
+			log.Printf("Failed to send mail: %v", err)
+		}
+		return
+	}
+	select {
+	case queue <- msg:
+	default:
+		log.Printf("⚠ Mail queue is full, dropping %q to %s", msg.Subject, msg.To)
+	}
+}
+
+func sendQueued(queue <-chan Message, done chan<- struct{}) {
+	defer close(done)
+	for msg := range queue {
+		if err := Default.Send(msg); err != nil {
+			log.Printf("Failed to send mail: %v", err)
+		}
+	}
+}
+
+// Stop sends the messages still queued, waiting until they're done or ctx
+// ends. Nothing may be enqueued once it's called.
+func Stop(ctx context.Context) error {
+	if queue == nil {
+		return nil
+	}
+	close(queue)
+	queue = nil
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("unsent mail left in the queue: %w", ctx.Err())
+	}
+}
+
+// Link builds a frontend URL for use in an email
+func Link(path string, params url.Values) string {
+	link := BaseURL + path
+	if len(params) > 0 {
+		link += "?" + params.Encode()
+	}
+	return link
+}
+
+func format(from string, msg Message) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", msg.Subject)
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	return []byte(b.String())
+}
+
+// SMTPMailer sends mail through an SMTP server, using STARTTLS when offered.
+// Each send, from dialing to QUIT, has to finish within the timeout.
+type SMTPMailer struct {
+	host    string
+	addr    string
+	auth    smtp.Auth
+	from    string
+	timeout time.Duration
+}
+
+func NewSMTPMailer(host string, port int, username, password, from string, timeout time.Duration) *SMTPMailer {
+	m := &SMTPMailer{host: host, addr: net.JoinHostPort(host, strconv.Itoa(port)), from: from, timeout: timeout}
+	if username != "" {
+		m.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return m
+}
+
+func (m *SMTPMailer) Send(msg Message) error {
+	if err := m.send(msg); err != nil {
+		return fmt.Errorf("failed to send mail to %s: %w", msg.To, err)
+	}
+	return nil
+}
+
+func (m *SMTPMailer) send(msg Message) error {
+	sender := m.from
+	if start := strings.LastIndex(sender, "<"); start >= 0 {
+		sender = strings.TrimSuffix(sender[start+1:], ">")
+	}
+
+	conn, err := (&net.Dialer{Timeout: m.timeout}).Dial("tcp", m.addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(m.timeout)); err != nil {
+		return err
+	}
+
+	client, err := smtp.NewClient(conn, m.host)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: m.host}); err != nil {
+			return err
+		}
+	}
+	if m.auth != nil {
+		if err := client.Auth(m.auth); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(sender); err != nil {
+		return err
+	}
+	if err := client.Rcpt(msg.To); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(format(m.from, msg)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// FileMailer writes each message to its own file, for local testing
+type FileMailer struct {
+	Dir  string
+	From string
+}
+
+func (m FileMailer) Send(msg Message) error {
+	if err := os.MkdirAll(m.Dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create mail directory: %w", err)
+	}
+	name := fmt.Sprintf("%d-%s.eml", time.Now().UnixNano(), strings.NewReplacer("@", "_at_", "/", "_").Replace(msg.To))
+	if err := os.WriteFile(filepath.Join(m.Dir, name), format(m.From, msg), 0o600); err != nil {
+		return fmt.Errorf("failed to write mail: %w", err)
+	}
+	log.Printf("📧 Wrote mail for %s to %s", msg.To, filepath.Join(m.Dir, name))
+	return nil
+}
+
+// LogMailer prints messages to the server log instead of sending them
+type LogMailer struct{}
+
+func (LogMailer) Send(msg Message) error {
+	log.Printf("📧 Mail to %s: %s\n%s", msg.To, msg.Subject, msg.Body)
+	return nil
+}
@@ -5,6 +5,8 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/hsr-tools/backend/internal/database"
+	"github.com/hsr-tools/backend/internal/models"
 	"github.com/hsr-tools/backend/pkg/utils"
 )
 
@@ -40,7 +42,7 @@ func Auth() gin.HandlerFunc {
 			return
 		}
 
-		claims, err := utils.ValidateToken(parts[1])
+		claims, err := utils.ValidateAccessToken(parts[1])
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
 			c.Abort()
@@ -53,3 +55,41 @@ func Auth() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// AdminOnly must run after Auth. The role is read from the database rather
+// than the token so revoking admin takes effect immediately.
+func AdminOnly() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := c.Get("userID")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
+			c.Abort()
+			return
+		}
+
+		var user models.User
+		if err := database.DB.Select("id", "role").Where("id = ?", userID).First(&user).Error; err != nil || user.Role != models.RoleAdmin {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+			c.Abort()
+			return
+		}
+
+		c.Set("role", user.Role)
+		c.Next()
+	}
+}
+
+// OptionalAuth sets the user in context when a valid bearer token is sent,
+// but lets anonymous requests through
+func OptionalAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		parts := strings.Split(c.GetHeader("Authorization"), " ")
+		if len(parts) == 2 && parts[0] == "Bearer" {
+			if claims, err := utils.ValidateAccessToken(parts[1]); err == nil {
+				c.Set("userID", claims.UserID)
+				c.Set("email", claims.Email)
+			}
+		}
+		c.Next()
+	}
+}
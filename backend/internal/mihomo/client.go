@@ -0,0 +1,67 @@
+package mihomo
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// uidPattern matches a 9-digit HSR UID
+var uidPattern = regexp.MustCompile(`^[1-9][0-9]{8}$`)
+
+// ValidUID reports whether uid looks like a game UID
+func ValidUID(uid string) bool {
+	return uidPattern.MatchString(uid)
+}
+
+// UpstreamError is returned when Mihomo answers with a non-200 status
+type UpstreamError struct {
+	StatusCode int
+	Body       []byte
+}
+
+func (e *UpstreamError) Error() string {
+	return fmt.Sprintf("mihomo returned status %d", e.StatusCode)
+}
+
+// Client fetches raw showcase profiles from the Mihomo API
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// NewClient creates a client for the given base URL
+func NewClient(baseURL string, timeout time.Duration) *Client {
+	return &Client{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		HTTPClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// FetchProfile returns the parsed-profile JSON for a UID
+func (c *Client) FetchProfile(ctx context.Context, uid string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.BaseURL+"/sr_info_parsed/"+uid+"?lang=en", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch from mihomo: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mihomo response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &UpstreamError{StatusCode: resp.StatusCode, Body: body}
+	}
+	return body, nil
+}
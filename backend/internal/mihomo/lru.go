@@ -0,0 +1,60 @@
+package mihomo
+
+import (
+	"container/list"
+	"sync"
+)
+
+// lru is a fixed-size, concurrency-safe least-recently-used cache
+type lru struct {
+	mu       sync.Mutex
+	capacity int
+	items    map[string]*list.Element
+	order    *list.List
+}
+
+type lruItem struct {
+	key   string
+	value *entry
+}
+
+func newLRU(capacity int) *lru {
+	if capacity <= 0 {
+		capacity = 1
+	}
+	return &lru{
+		capacity: capacity,
+		items:    make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+func (l *lru) Get(key string) (*entry, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	el, ok := l.items[key]
+	if !ok {
+		return nil, false
+	}
+	l.order.MoveToFront(el)
+	return el.Value.(*lruItem).value, true
+}
+
+func (l *lru) Add(key string, value *entry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if el, ok := l.items[key]; ok {
+		el.Value.(*lruItem).value = value
+		l.order.MoveToFront(el)
+		return
+	}
+
+	l.items[key] = l.order.PushFront(&lruItem{key: key, value: value})
+	if l.order.Len() > l.capacity {
+		oldest := l.order.Back()
+		l.order.Remove(oldest)
+		delete(l.items, oldest.Value.(*lruItem).key)
+	}
+}
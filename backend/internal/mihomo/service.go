@@ -0,0 +1,253 @@
+package mihomo
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/hsr-tools/backend/internal/config"
+	"github.com/hsr-tools/backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Cache states reported alongside a profile
+const (
+	CacheHit   = "HIT"
+	CacheStale = "STALE"
+	CacheMiss  = "MISS"
+)
+
+// ErrInvalidUID is returned for UIDs that can't be a game UID
+var ErrInvalidUID = errors.New("invalid UID")
+
+const (
+	// refreshBackoff is how long a stale entry is served as is after a
+	// background refresh fails, instead of retrying on every request
+	refreshBackoff = time.Minute
+
+	// defaultFetchTimeout bounds upstream fetches when the client has no
+	// timeout of its own
+	defaultFetchTimeout = 30 * time.Second
+)
+
+// Profiles is the shared profile service, set up by Setup
+var Profiles *Service
+
+type entry struct {
+	data       []byte
+	fetchedAt  time.Time
+	expiresAt  time.Time
+	staleUntil time.Time
+	retryAfter time.Time // No background refresh before this
+}
+
+// Result is a profile returned from the cache or upstream
+type Result struct {
+	Data      []byte
+	FetchedAt time.Time
+	Cache     string
+}
+
+// Service serves Mihomo profiles through an in-memory LRU backed by the
+// user_profiles table. Fresh entries are served directly, stale entries
+// are served while a background refresh runs, and anything older is
+// fetched synchronously.
+type Service struct {
+	client   *Client
+	db       *gorm.DB
+	memory   *lru
+	ttl      time.Duration
+	staleTTL time.Duration
+
+	mu       sync.Mutex
+	inflight map[string]*call
+}
+
+type call struct {
+	done  chan struct{}
+	entry *entry
+	err   error
+}
+
+// Setup creates the shared profile service
+func Setup(cfg *config.Config, db *gorm.DB) {
+	Profiles = NewService(NewClient(cfg.MihomoBaseURL, cfg.MihomoTimeout), db,
+		cfg.ProfileCacheSize, cfg.ProfileCacheTTL, cfg.ProfileStaleTTL)
+}
+
+// NewService creates a profile service
+func NewService(client *Client, db *gorm.DB, size int, ttl, staleTTL time.Duration) *Service {
+	return &Service{
+		client:   client,
+		db:       db,
+		memory:   newLRU(size),
+		ttl:      ttl,
+		staleTTL: staleTTL,
+		inflight: make(map[string]*call),
+	}
+}
+
+// Get returns the profile for uid, fetching it upstream only when needed
+func (s *Service) Get(ctx context.Context, uid string) (*Result, error) {
+	if !ValidUID(uid) {
+		return nil, ErrInvalidUID
+	}
+
+	now := time.Now()
+	e := s.lookup(uid)
+
+	if e != nil && now.Before(e.expiresAt) {
+		return &Result{Data: e.data, FetchedAt: e.fetchedAt, Cache: CacheHit}, nil
+	}
+
+	if e != nil && now.Before(e.staleUntil) {
+		if now.After(e.retryAfter) {
+			go s.refreshInBackground(uid, e)
+		}
+		return &Result{Data: e.data, FetchedAt: e.fetchedAt, Cache: CacheStale}, nil
+	}
+
+	fresh, err := s.refresh(ctx, uid)
+	if err != nil {
+		// Serve whatever we have rather than fail on a transient error
+		if e != nil && transient(err) {
+			return &Result{Data: e.data, FetchedAt: e.fetchedAt, Cache: CacheStale}, nil
+		}
+		return nil, err
+	}
+	return &Result{Data: fresh.data, FetchedAt: fresh.fetchedAt, Cache: CacheMiss}, nil
+}
+
+// refreshInBackground refreshes a stale entry. On failure the entry is
+// kept, marked to wait refreshBackoff before the next attempt.
+func (s *Service) refreshInBackground(uid string, stale *entry) {
+	if _, err := s.refresh(context.Background(), uid); err != nil {
+		log.Printf("Warning: background refresh for %s failed: %v", uid, err)
+
+		// Entries are shared with readers, so mark a copy, unless another
+		// fetch has replaced the entry meanwhile
+		if current, ok := s.memory.Get(uid); ok && current != stale {
+			return
+		}
+		backoff := *stale
+		backoff.retryAfter = time.Now().Add(refreshBackoff)
+		s.memory.Add(uid, &backoff)
+	}
+}
+
+// Refresh bypasses the cache and fetches uid upstream
+func (s *Service) Refresh(ctx context.Context, uid string) (*Result, error) {
+	if !ValidUID(uid) {
+		return nil, ErrInvalidUID
+	}
+	e, err := s.refresh(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	return &Result{Data: e.data, FetchedAt: e.fetchedAt, Cache: CacheMiss}, nil
+}
+
+// lookup checks memory first, then the database
+func (s *Service) lookup(uid string) *entry {
+	if e, ok := s.memory.Get(uid); ok {
+		return e
+	}
+	if s.db == nil {
+		return nil
+	}
+
+	var profile models.UserProfile
+	if err := s.db.Where("uid = ?", uid).First(&profile).Error; err != nil {
+		return nil
+	}
+
+	e := &entry{
+		data:       profile.Data,
+		fetchedAt:  profile.FetchedAt,
+		expiresAt:  profile.ExpiresAt,
+		staleUntil: profile.StaleUntil,
+	}
+	s.memory.Add(uid, e)
+	return e
+}
+
+// refresh fetches uid upstream, collapsing concurrent fetches for the
+// same UID into one request
+func (s *Service) refresh(ctx context.Context, uid string) (*entry, error) {
+	s.mu.Lock()
+	if c, ok := s.inflight[uid]; ok {
+		s.mu.Unlock()
+		select {
+		case <-c.done:
+			return c.entry, c.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	c := &call{done: make(chan struct{})}
+	s.inflight[uid] = c
+	s.mu.Unlock()
+
+	// Other callers wait on this fetch, so it must not end when the first
+	// caller's request does. It gets its own deadline instead.
+	fetchCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), s.fetchTimeout())
+	c.entry, c.err = s.fetch(fetchCtx, uid)
+	cancel()
+	close(c.done)
+
+	s.mu.Lock()
+	delete(s.inflight, uid)
+	s.mu.Unlock()
+
+	return c.entry, c.err
+}
+
+func (s *Service) fetchTimeout() time.Duration {
+	if s.client.HTTPClient != nil && s.client.HTTPClient.Timeout > 0 {
+		return s.client.HTTPClient.Timeout
+	}
+	return defaultFetchTimeout
+}
+
+func (s *Service) fetch(ctx context.Context, uid string) (*entry, error) {
+	data, err := s.client.FetchProfile(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	e := &entry{
+		data:       data,
+		fetchedAt:  now,
+		expiresAt:  now.Add(s.ttl),
+		staleUntil: now.Add(s.ttl + s.staleTTL),
+	}
+	s.memory.Add(uid, e)
+
+	if s.db != nil {
+		profile := models.UserProfile{
+			UID:        uid,
+			Data:       data,
+			FetchedAt:  e.fetchedAt,
+			ExpiresAt:  e.expiresAt,
+			StaleUntil: e.staleUntil,
+		}
+		if err := s.db.WithContext(ctx).Clauses(clause.OnConflict{UpdateAll: true}).Create(&profile).Error; err != nil {
+			log.Printf("Warning: failed to persist profile %s: %v", uid, err)
+		}
+	}
+	return e, nil
+}
+
+// transient reports whether an upstream failure is worth masking with
+// stale data; client errors such as an unknown UID are not
+func transient(err error) bool {
+	var upstream *UpstreamError
+	if errors.As(err, &upstream) {
+		return upstream.StatusCode >= 500 || upstream.StatusCode == 429
+	}
+	return true
+}
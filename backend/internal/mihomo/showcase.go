@@ -0,0 +1,378 @@
+package mihomo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ShowcaseSubstat is a relic substat with its roll count
+type ShowcaseSubstat struct {
+	Type  string  `json:"type"`
+	Field string  `json:"field"`
+	Value float64 `json:"value"`
+	Rolls int     `json:"rolls"`
+}
+
+// ShowcaseStat is a relic main stat
+type ShowcaseStat struct {
+	Type  string  `json:"type"`
+	Field string  `json:"field"`
+	Value float64 `json:"value"`
+}
+
+// ShowcaseRelic is an equipped relic or planar ornament
+type ShowcaseRelic struct {
+	ID       string            `json:"id"`
+	Slot     string            `json:"slot"`
+	SetName  string            `json:"setName"`
+	Rarity   int               `json:"rarity"`
+	Level    int               `json:"level"`
+	MainStat ShowcaseStat      `json:"mainStat"`
+	Substats []ShowcaseSubstat `json:"substats"`
+}
+
+// ShowcaseLightConeStats are a light cone's base stats
+type ShowcaseLightConeStats struct {
+	HP  float64 `json:"hp"`
+	Atk float64 `json:"atk"`
+	Def float64 `json:"def"`
+}
+
+// ShowcaseLightCone is an equipped light cone
+type ShowcaseLightCone struct {
+	ID              string                 `json:"id"`
+	Name            string                 `json:"name"`
+	Rarity          int                    `json:"rarity"`
+	Level           int                    `json:"level"`
+	Superimposition int                    `json:"superimposition"`
+	Stats           ShowcaseLightConeStats `json:"stats"`
+}
+
+// ShowcaseStats are a character's final attributes (base + additions).
+// Percentage stats are expressed in percent, e.g. 65.4 for 65.4% CRIT Rate.
+type ShowcaseStats struct {
+	HP          float64 `json:"hp"`
+	Atk         float64 `json:"atk"`
+	Def         float64 `json:"def"`
+	Spd         float64 `json:"spd"`
+	CritRate    float64 `json:"critRate"`
+	CritDmg     float64 `json:"critDmg"`
+	EffectHit   float64 `json:"effectHit"`
+	EffectRes   float64 `json:"effectRes"`
+	BreakEffect float64 `json:"breakEffect"`
+	EnergyRegen float64 `json:"energyRegen"`
+}
+
+// ShowcaseCharacter is a character from the player's showcase
+type ShowcaseCharacter struct {
+	ID        string             `json:"id"`
+	Name      string             `json:"name"`
+	Rarity    int                `json:"rarity"`
+	Element   string             `json:"element"`
+	Path      string             `json:"path"`
+	Level     int                `json:"level"`
+	Eidolon   int                `json:"eidolon"`
+	Stats     ShowcaseStats      `json:"stats"`
+	LightCone *ShowcaseLightCone `json:"lightCone,omitempty"`
+	Relics    []ShowcaseRelic    `json:"relics"`
+}
+
+// ShowcaseProfile is the stable profile format served to clients
+type ShowcaseProfile struct {
+	UID        string              `json:"uid"`
+	Nickname   string              `json:"nickname"`
+	Level      int                 `json:"level"`
+	Signature  string              `json:"signature,omitempty"`
+	Characters []ShowcaseCharacter `json:"characters"`
+}
+
+// elementMap normalizes upstream element names
+var elementMap = map[string]string{
+	"Physical":  "Physical",
+	"Fire":      "Fire",
+	"Ice":       "Ice",
+	"Thunder":   "Lightning",
+	"Lightning": "Lightning",
+	"Wind":      "Wind",
+	"Quantum":   "Quantum",
+	"Imaginary": "Imaginary",
+}
+
+// slotMap maps upstream relic types to slots
+var slotMap = map[int]string{
+	1: "head",
+	2: "hands",
+	3: "body",
+	4: "feet",
+	5: "orb",
+	6: "rope",
+}
+
+// flexString accepts both JSON strings and numbers
+type flexString string
+
+func (f *flexString) UnmarshalJSON(data []byte) error {
+	data = bytes.Trim(data, `"`)
+	if string(data) == "null" {
+		*f = ""
+		return nil
+	}
+	*f = flexString(data)
+	return nil
+}
+
+// flexInt accepts both JSON numbers and numeric strings
+type flexInt int
+
+func (f *flexInt) UnmarshalJSON(data []byte) error {
+	data = bytes.Trim(data, `"`)
+	if len(data) == 0 || string(data) == "null" {
+		*f = 0
+		return nil
+	}
+	v, err := strconv.ParseFloat(string(data), 64)
+	if err != nil {
+		return fmt.Errorf("invalid number %q", data)
+	}
+	*f = flexInt(v)
+	return nil
+}
+
+type rawAttribute struct {
+	Field   string  `json:"field"`
+	Name    string  `json:"name"`
+	Value   float64 `json:"value"`
+	Display string  `json:"display"`
+	Percent *bool   `json:"percent"`
+	Count   flexInt `json:"count"`
+}
+
+type rawNamed struct {
+	ID   flexString `json:"id"`
+	Name string     `json:"name"`
+}
+
+type rawRelic struct {
+	ID        flexString     `json:"id"`
+	Name      string         `json:"name"`
+	SetName   string         `json:"set_name"`
+	Rarity    flexInt        `json:"rarity"`
+	Type      flexInt        `json:"type"`
+	Level     flexInt        `json:"level"`
+	MainAffix rawAttribute   `json:"main_affix"`
+	SubAffix  []rawAttribute `json:"sub_affix"`
+}
+
+type rawLightCone struct {
+	ID         flexString     `json:"id"`
+	Name       string         `json:"name"`
+	Rarity     flexInt        `json:"rarity"`
+	Level      flexInt        `json:"level"`
+	Rank       flexInt        `json:"rank"`
+	Attributes []rawAttribute `json:"attributes"`
+}
+
+type rawCharacter struct {
+	ID         flexString     `json:"id"`
+	Name       string         `json:"name"`
+	Rarity     flexInt        `json:"rarity"`
+	Element    rawNamed       `json:"element"`
+	Path       rawNamed       `json:"path"`
+	Level      flexInt        `json:"level"`
+	Rank       flexInt        `json:"rank"`
+	Attributes []rawAttribute `json:"attributes"`
+	Additions  []rawAttribute `json:"additions"`
+	LightCone  *rawLightCone  `json:"light_cone"`
+	Relics     []rawRelic     `json:"relics"`
+}
+
+type rawProfile struct {
+	Player *struct {
+		UID       flexString `json:"uid"`
+		Nickname  string     `json:"nickname"`
+		Level     flexInt    `json:"level"`
+		Signature string     `json:"signature"`
+	} `json:"player"`
+	Characters []rawCharacter `json:"characters"`
+}
+
+// ParseProfile converts a raw Mihomo sr_info_parsed response into a
+// ShowcaseProfile. Unknown fields are ignored, and missing ones are left
+// at their zero value.
+func ParseProfile(data []byte) (*ShowcaseProfile, error) {
+	var raw rawProfile
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse mihomo profile: %w", err)
+	}
+	if raw.Player == nil {
+		return nil, fmt.Errorf("mihomo profile has no player")
+	}
+
+	profile := &ShowcaseProfile{
+		UID:        string(raw.Player.UID),
+		Nickname:   raw.Player.Nickname,
+		Level:      int(raw.Player.Level),
+		Signature:  raw.Player.Signature,
+		Characters: make([]ShowcaseCharacter, 0, len(raw.Characters)),
+	}
+	for _, char := range raw.Characters {
+		profile.Characters = append(profile.Characters, parseCharacter(char))
+	}
+	return profile, nil
+}
+
+func parseCharacter(char rawCharacter) ShowcaseCharacter {
+	element, ok := elementMap[char.Element.Name]
+	if !ok {
+		element = "Physical"
+	}
+
+	result := ShowcaseCharacter{
+		ID:      string(char.ID),
+		Name:    char.Name,
+		Rarity:  int(char.Rarity),
+		Element: element,
+		Path:    char.Path.Name,
+		Level:   int(char.Level),
+		Eidolon: int(char.Rank),
+		Stats:   parseStats(char.Attributes, char.Additions),
+		Relics:  make([]ShowcaseRelic, 0, len(char.Relics)),
+	}
+
+	if lc := char.LightCone; lc != nil {
+		result.LightCone = &ShowcaseLightCone{
+			ID:              string(lc.ID),
+			Name:            lc.Name,
+			Rarity:          int(lc.Rarity),
+			Level:           int(lc.Level),
+			Superimposition: int(lc.Rank),
+		}
+		for _, attr := range lc.Attributes {
+			switch attr.Field {
+			case "hp":
+				result.LightCone.Stats.HP = math.Round(attr.Value)
+			case "atk":
+				result.LightCone.Stats.Atk = math.Round(attr.Value)
+			case "def":
+				result.LightCone.Stats.Def = math.Round(attr.Value)
+			}
+		}
+	}
+
+	for _, relic := range char.Relics {
+		result.Relics = append(result.Relics, parseRelic(relic))
+	}
+	return result
+}
+
+// parseStats sums base attributes and additions into final stats
+func parseStats(attributes, additions []rawAttribute) ShowcaseStats {
+	totals := make(map[string]float64)
+	for _, attr := range attributes {
+		totals[attr.Field] += attr.Value
+	}
+	for _, attr := range additions {
+		totals[attr.Field] += attr.Value
+	}
+
+	// Every character has 5% CRIT Rate and 50% CRIT DMG even when the
+	// upstream omits them
+	if _, ok := totals["crit_rate"]; !ok {
+		totals["crit_rate"] = 0.05
+	}
+	if _, ok := totals["crit_dmg"]; !ok {
+		totals["crit_dmg"] = 0.5
+	}
+	if _, ok := totals["sp_rate"]; !ok {
+		totals["sp_rate"] = 1
+	}
+
+	return ShowcaseStats{
+		HP:          math.Round(totals["hp"]),
+		Atk:         math.Round(totals["atk"]),
+		Def:         math.Round(totals["def"]),
+		Spd:         math.Round(totals["spd"]*10) / 10,
+		CritRate:    percent(totals["crit_rate"]),
+		CritDmg:     percent(totals["crit_dmg"]),
+		EffectHit:   percent(totals["effect_hit"]),
+		EffectRes:   percent(totals["effect_res"]),
+		BreakEffect: percent(totals["break_dmg"]),
+		EnergyRegen: percent(totals["sp_rate"]),
+	}
+}
+
+func parseRelic(relic rawRelic) ShowcaseRelic {
+	slot, ok := slotMap[int(relic.Type)]
+	if !ok {
+		slot = "head"
+	}
+
+	result := ShowcaseRelic{
+		ID:      string(relic.ID),
+		Slot:    slot,
+		SetName: relic.SetName,
+		Rarity:  int(relic.Rarity),
+		Level:   int(relic.Level),
+		MainStat: ShowcaseStat{
+			Type:  statName(relic.MainAffix),
+			Field: relic.MainAffix.Field,
+			Value: mainStatValue(relic.MainAffix),
+		},
+		Substats: make([]ShowcaseSubstat, 0, len(relic.SubAffix)),
+	}
+
+	for _, sub := range relic.SubAffix {
+		rolls := int(sub.Count)
+		if rolls == 0 {
+			rolls = 1
+		}
+		result.Substats = append(result.Substats, ShowcaseSubstat{
+			Type:  statName(sub),
+			Field: sub.Field,
+			Value: affixValue(sub),
+			Rolls: rolls,
+		})
+	}
+	return result
+}
+
+// statName returns the stat name used in substat-values.json, which
+// tells flat and percentage ATK/HP/DEF apart by a "%" suffix
+func statName(attr rawAttribute) string {
+	switch attr.Name {
+	case "ATK", "HP", "DEF":
+		if attr.Percent != nil && *attr.Percent {
+			return attr.Name + "%"
+		}
+	}
+	return attr.Name
+}
+
+// mainStatValue prefers the upstream display string, which is already
+// rounded the way the game shows it
+func mainStatValue(attr rawAttribute) float64 {
+	if v, err := strconv.ParseFloat(strings.TrimSuffix(attr.Display, "%"), 64); err == nil && v != 0 {
+		return v
+	}
+	return affixValue(attr)
+}
+
+// affixValue returns percentage affixes in percent and flat ones as-is
+func affixValue(attr rawAttribute) float64 {
+	isPercent := strings.Contains(attr.Name, "%") || strings.Contains(attr.Field, "_")
+	if attr.Percent != nil {
+		isPercent = *attr.Percent
+	}
+	if isPercent {
+		return percent(attr.Value)
+	}
+	return math.Round(attr.Value*100) / 100
+}
+
+func percent(v float64) float64 {
+	return math.Round(v*1000) / 10
+}
@@ -0,0 +1,177 @@
+package mihomo
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const sampleProfile = `{
+	"player": {"uid": 800123456, "nickname": "Trailblazer", "level": "70", "signature": "hi"},
+	"characters": [{
+		"id": "1102",
+		"name": "Seele",
+		"rarity": 5,
+		"element": {"id": "Quantum", "name": "Quantum"},
+		"path": {"id": "Rogue", "name": "The Hunt"},
+		"level": 80,
+		"rank": 2,
+		"attributes": [
+			{"field": "atk", "value": 1500.4},
+			{"field": "spd", "value": 115},
+			{"field": "crit_rate", "value": 0.05}
+		],
+		"additions": [
+			{"field": "atk", "value": 800.2},
+			{"field": "spd", "value": 20.26},
+			{"field": "crit_rate", "value": 0.6},
+			{"field": "crit_dmg", "value": 0.8}
+		],
+		"light_cone": {
+			"id": 23001,
+			"name": "In the Night",
+			"rarity": 5,
+			"level": 80,
+			"rank": 1,
+			"attributes": [{"field": "hp", "value": 1058.4}, {"field": "atk", "value": 582.12}]
+		},
+		"relics": [{
+			"id": "61011",
+			"set_name": "Genius of Brilliant Stars",
+			"rarity": 5,
+			"type": 3,
+			"level": 15,
+			"main_affix": {"field": "crit_rate", "name": "CRIT Rate", "value": 0.324, "display": "32.4%"},
+			"sub_affix": [
+				{"field": "atk", "name": "ATK", "value": 0.0864, "percent": true, "count": 2},
+				{"field": "atk", "name": "ATK", "value": 42.33, "percent": false, "count": "1"},
+				{"field": "spd", "name": "SPD", "value": 2.3}
+			]
+		}]
+	}]
+}`
+
+func TestParseProfile(t *testing.T) {
+	profile, err := ParseProfile([]byte(sampleProfile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if profile.UID != "800123456" || profile.Nickname != "Trailblazer" || profile.Level != 70 || profile.Signature != "hi" {
+		t.Errorf("player = %+v, want uid 800123456, Trailblazer, level 70", profile)
+	}
+	if len(profile.Characters) != 1 {
+		t.Fatalf("got %d characters, want 1", len(profile.Characters))
+	}
+
+	char := profile.Characters[0]
+	if char.ID != "1102" || char.Element != "Quantum" || char.Path != "The Hunt" || char.Level != 80 || char.Eidolon != 2 {
+		t.Errorf("character = %+v", char)
+	}
+
+	wantStats := ShowcaseStats{Atk: 2301, Spd: 135.3, CritRate: 65, CritDmg: 80, EnergyRegen: 100}
+	if char.Stats != wantStats {
+		t.Errorf("Stats = %+v, want %+v", char.Stats, wantStats)
+	}
+
+	wantLightCone := &ShowcaseLightCone{
+		ID:              "23001",
+		Name:            "In the Night",
+		Rarity:          5,
+		Level:           80,
+		Superimposition: 1,
+		Stats:           ShowcaseLightConeStats{HP: 1058, Atk: 582},
+	}
+	if !reflect.DeepEqual(char.LightCone, wantLightCone) {
+		t.Errorf("LightCone = %+v, want %+v", char.LightCone, wantLightCone)
+	}
+
+	wantRelic := ShowcaseRelic{
+		ID:       "61011",
+		Slot:     "body",
+		SetName:  "Genius of Brilliant Stars",
+		Rarity:   5,
+		Level:    15,
+		MainStat: ShowcaseStat{Type: "CRIT Rate", Field: "crit_rate", Value: 32.4},
+		Substats: []ShowcaseSubstat{
+			{Type: "ATK%", Field: "atk", Value: 8.6, Rolls: 2},
+			{Type: "ATK", Field: "atk", Value: 42.33, Rolls: 1},
+			{Type: "SPD", Field: "spd", Value: 2.3, Rolls: 1},
+		},
+	}
+	if len(char.Relics) != 1 || !reflect.DeepEqual(char.Relics[0], wantRelic) {
+		t.Errorf("Relics = %+v, want [%+v]", char.Relics, wantRelic)
+	}
+}
+
+func TestParseProfileEmptyFields(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want *ShowcaseProfile
+	}{
+		{
+			name: "no characters",
+			body: `{"player": {}}`,
+			want: &ShowcaseProfile{Characters: []ShowcaseCharacter{}},
+		},
+		{
+			name: "empty and null player fields",
+			body: `{"player": {"uid": "", "nickname": "", "level": ""}, "characters": null}`,
+			want: &ShowcaseProfile{Characters: []ShowcaseCharacter{}},
+		},
+		{
+			name: "null showcase values",
+			body: `{"player": {"uid": null, "level": null}, "characters": [{"id": null, "level": null, "rank": "", "element": {}, "light_cone": null, "relics": null}]}`,
+			want: &ShowcaseProfile{Characters: []ShowcaseCharacter{{
+				Element: "Physical",
+				Stats:   ShowcaseStats{CritRate: 5, CritDmg: 50, EnergyRegen: 100},
+				Relics:  []ShowcaseRelic{},
+			}}},
+		},
+		{
+			name: "empty relic",
+			body: `{"player": {}, "characters": [{"relics": [{"type": "", "main_affix": {}, "sub_affix": [{"name": "SPD", "count": ""}]}]}]}`,
+			want: &ShowcaseProfile{Characters: []ShowcaseCharacter{{
+				Element: "Physical",
+				Stats:   ShowcaseStats{CritRate: 5, CritDmg: 50, EnergyRegen: 100},
+				Relics: []ShowcaseRelic{{
+					Slot:     "head",
+					Substats: []ShowcaseSubstat{{Type: "SPD", Rolls: 1}},
+				}},
+			}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseProfile([]byte(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseProfile() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseProfileErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{name: "not JSON", body: `<html>`, want: "failed to parse"},
+		{name: "no player", body: `{"characters": []}`, want: "no player"},
+		{name: "null player", body: `{"player": null}`, want: "no player"},
+		{name: "non-numeric level", body: `{"player": {"level": "seventy"}}`, want: "invalid number"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseProfile([]byte(tt.body))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("ParseProfile() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Account links a user to an OAuth login provider
+type Account struct {
+	ID                uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID            uuid.UUID  `gorm:"type:uuid;not null;index" json:"userId"`
+	Type              string     `gorm:"not null;size:20" json:"type"` // "oidc" or "oauth"
+	Provider          string     `gorm:"not null;size:50;uniqueIndex:idx_account_provider" json:"provider"`
+	ProviderAccountID string     `gorm:"not null;uniqueIndex:idx_account_provider" json:"providerAccountId"`
+	Email             string     `gorm:"" json:"email"`
+	AccessToken       string     `gorm:"type:text" json:"-"`
+	RefreshToken      string     `gorm:"type:text" json:"-"`
+	IDToken           string     `gorm:"type:text" json:"-"`
+	ExpiresAt         *time.Time `json:"-"`
+	TokenType         string     `gorm:"size:20" json:"-"`
+	Scope             string     `gorm:"" json:"scope"`
+	CreatedAt         time.Time  `json:"createdAt"`
+	UpdatedAt         time.Time  `json:"updatedAt"`
+
+	// Relations
+	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// OAuthState holds a pending authorization between the redirect to the
+// provider and its callback
+type OAuthState struct {
+	State        string     `gorm:"primaryKey;size:64" json:"-"`
+	Provider     string     `gorm:"not null;size:50" json:"-"`
+	CodeVerifier string     `gorm:"not null;size:64" json:"-"`
+	UserID       *uuid.UUID `gorm:"type:uuid" json:"-"` // Set when linking to a logged in user
+	RedirectURL  string     `gorm:"size:500" json:"-"`  // Frontend page to return to
+	ExpiresAt    time.Time  `gorm:"not null;index" json:"-"`
+	CreatedAt    time.Time  `json:"-"`
+}
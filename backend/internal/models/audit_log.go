@@ -0,0 +1,31 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Audit log actions
+const (
+	AuditCreate = "create"
+	AuditUpdate = "update"
+	AuditDelete = "delete"
+)
+
+// AuditLog records a change made through the admin API
+type AuditLog struct {
+	ID        uuid.UUID       `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID    uuid.UUID       `gorm:"type:uuid;not null;index" json:"userId"`
+	Action    string          `gorm:"not null;size:20" json:"action"`
+	Entity    string          `gorm:"not null;size:50;index:idx_audit_entity" json:"entity"` // e.g. "banner", "character"
+	EntityID  string          `gorm:"not null;size:50;index:idx_audit_entity" json:"entityId"`
+	Before    json.RawMessage `gorm:"type:jsonb" json:"before,omitempty"`
+	After     json.RawMessage `gorm:"type:jsonb" json:"after,omitempty"`
+	IP        string          `gorm:"size:45" json:"ip"`
+	CreatedAt time.Time       `gorm:"index" json:"createdAt"`
+
+	// Relations
+	User *User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
@@ -30,12 +30,14 @@ type Character struct {
 	Rarity       int    `gorm:"not null;default:4" json:"rarity"`
 	BaseSpeed    int    `gorm:"not null;default:100" json:"baseSpeed"`
 	ReleaseOrder int    `gorm:"not null;default:0;index" json:"releaseOrder"`
+	Seeded       bool   `gorm:"not null;default:false" json:"-"` // Written by seed, which may prune it
 
 	// Relations
 	Element        Element         `gorm:"foreignKey:ElementID" json:"element,omitempty"`
 	Path           Path            `gorm:"foreignKey:PathID" json:"path,omitempty"`
 	Skills         *CharacterSkill `gorm:"foreignKey:CharacterID" json:"skills,omitempty"`
 	Build          *CharacterBuild `gorm:"foreignKey:CharacterID" json:"build,omitempty"`
+	Eidolons       []Eidolon       `gorm:"foreignKey:CharacterID" json:"eidolons,omitempty"`
 	UserCharacters []UserCharacter `gorm:"foreignKey:CharacterID" json:"-"`
 }
 
@@ -54,6 +56,7 @@ type CharacterSkill struct {
 	BaseAtk         int     `gorm:"default:500" json:"baseAtk"`
 	BaseCritRate    float64 `gorm:"type:decimal(4,2);default:0.05" json:"baseCritRate"`
 	BaseCritDmg     float64 `gorm:"type:decimal(4,2);default:0.50" json:"baseCritDmg"`
+	Seeded          bool    `gorm:"not null;default:false" json:"-"`
 
 	// Relations
 	Character Character `gorm:"foreignKey:CharacterID" json:"-"`
@@ -67,6 +70,7 @@ type CharacterBuild struct {
 	FeetMain    string `gorm:"size:50" json:"feetMain"`
 	OrbMain     string `gorm:"size:50" json:"orbMain"`
 	RopeMain    string `gorm:"size:50" json:"ropeMain"`
+	Seeded      bool   `gorm:"not null;default:false" json:"-"`
 
 	// Relations
 	Character Character               `gorm:"foreignKey:CharacterID" json:"-"`
@@ -74,6 +78,22 @@ type CharacterBuild struct {
 	Substats  []CharacterBuildSubstat `gorm:"foreignKey:BuildID" json:"substats,omitempty"`
 }
 
+// MaxEidolon is the highest eidolon rank a character can reach
+const MaxEidolon = 6
+
+// Eidolon is one of a character's six eidolon effects
+type Eidolon struct {
+	ID          int    `gorm:"primaryKey;autoIncrement" json:"id"`
+	CharacterID string `gorm:"not null;size:50;uniqueIndex:idx_character_eidolon_rank" json:"characterId"`
+	Rank        int    `gorm:"not null;uniqueIndex:idx_character_eidolon_rank" json:"rank"` // 1-6
+	Name        string `gorm:"not null;size:100" json:"name"`
+	Description string `gorm:"type:text" json:"description"`
+	Seeded      bool   `gorm:"not null;default:false" json:"-"`
+
+	// Relations
+	Character Character `gorm:"foreignKey:CharacterID" json:"-"`
+}
+
 // RelicSet represents a relic/planar set
 type RelicSet struct {
 	ID   int    `gorm:"primaryKey;autoIncrement" json:"id"`
@@ -0,0 +1,57 @@
+package models
+
+// Enemy represents an enemy from the battle catalog
+type Enemy struct {
+	ID        string `gorm:"primaryKey;size:50" json:"id"`
+	Name      string `gorm:"not null;size:100" json:"name"`
+	Type      string `gorm:"size:20;index" json:"type"` // "boss", "elite", "custom"
+	HP        int    `gorm:"not null" json:"hp"`
+	Speed     int    `gorm:"not null;default:100" json:"speed"`
+	Def       int    `gorm:"not null;default:1000" json:"def"`
+	Toughness int    `gorm:"not null;default:120" json:"toughness"`
+	ImageURL  string `gorm:"size:500" json:"imageUrl"`
+	Seeded    bool   `gorm:"not null;default:false" json:"-"`
+
+	// Relations
+	Weaknesses  []EnemyWeakness   `gorm:"foreignKey:EnemyID" json:"weaknesses,omitempty"`
+	Resistances []EnemyResistance `gorm:"foreignKey:EnemyID" json:"resistances,omitempty"`
+	Variants    []EnemyVariant    `gorm:"foreignKey:EnemyID" json:"variants,omitempty"`
+}
+
+// EnemyWeakness links an enemy to an element it is weak to
+type EnemyWeakness struct {
+	ID        int    `gorm:"primaryKey;autoIncrement" json:"id"`
+	EnemyID   string `gorm:"not null;size:50;index" json:"enemyId"`
+	ElementID int    `gorm:"not null;index" json:"elementId"`
+
+	// Relations
+	Enemy   Enemy   `gorm:"foreignKey:EnemyID" json:"-"`
+	Element Element `gorm:"foreignKey:ElementID" json:"element,omitempty"`
+}
+
+// EnemyResistance contains an enemy's resistance to an element
+type EnemyResistance struct {
+	ID        int     `gorm:"primaryKey;autoIncrement" json:"id"`
+	EnemyID   string  `gorm:"not null;size:50;index" json:"enemyId"`
+	ElementID int     `gorm:"not null;index" json:"elementId"`
+	Value     float64 `gorm:"type:decimal(4,2);default:0" json:"value"`
+
+	// Relations
+	Enemy   Enemy   `gorm:"foreignKey:EnemyID" json:"-"`
+	Element Element `gorm:"foreignKey:ElementID" json:"element,omitempty"`
+}
+
+// EnemyVariant is an alternate stat block for an enemy (e.g. a harder stage)
+type EnemyVariant struct {
+	ID        int    `gorm:"primaryKey;autoIncrement" json:"id"`
+	EnemyID   string `gorm:"not null;size:50;index" json:"enemyId"`
+	Name      string `gorm:"not null;size:100" json:"name"`
+	Level     int    `gorm:"default:90" json:"level"`
+	HP        int    `gorm:"not null" json:"hp"`
+	Speed     int    `gorm:"not null" json:"speed"`
+	Def       int    `gorm:"not null" json:"def"`
+	Toughness int    `gorm:"not null" json:"toughness"`
+
+	// Relations
+	Enemy Enemy `gorm:"foreignKey:EnemyID" json:"-"`
+}
@@ -1,15 +1,25 @@
 package models
 
-import "time"
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
 
 // Banner represents a character/weapon banner
 type Banner struct {
 	ID        int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	Slug      *string   `gorm:"uniqueIndex;size:50" json:"slug"` // ID from banners.json, e.g. "banner-3-8-1"
 	Name      string    `gorm:"not null;size:200" json:"name"`
-	Type      string    `gorm:"size:50" json:"type"` // "character", "weapon", "standard"
+	Phase     string    `gorm:"size:50" json:"phase"` // e.g. "3.8 Phase 1"
+	Type      string    `gorm:"size:50" json:"type"`  // "limited", "character", "weapon", "standard"
 	StartDate time.Time `gorm:"not null;index" json:"startDate"`
 	EndDate   time.Time `gorm:"not null;index" json:"endDate"`
 	ImageURL  string    `gorm:"size:500" json:"imageUrl"`
+	Seeded    bool      `gorm:"not null;default:false" json:"-"`
 
 	// Relations
 	Characters []BannerCharacter `gorm:"foreignKey:BannerID" json:"characters,omitempty"`
@@ -29,21 +39,67 @@ type BannerCharacter struct {
 
 // Code represents a redemption code
 type Code struct {
-	ID        int        `gorm:"primaryKey;autoIncrement" json:"id"`
-	Code      string     `gorm:"uniqueIndex;not null;size:50" json:"code"`
-	Rewards   string     `gorm:"type:text" json:"rewards"`
-	IsActive  bool       `gorm:"default:true;index" json:"isActive"`
-	ExpiresAt *time.Time `gorm:"index" json:"expiresAt"`
-	CreatedAt time.Time  `json:"createdAt"`
+	ID          int          `gorm:"primaryKey;autoIncrement" json:"id"`
+	Code        string       `gorm:"uniqueIndex;not null;size:50" json:"code"`
+	Rewards     string       `gorm:"type:text" json:"rewards"` // Display text, e.g. "60 Stellar Jade + 1 Fuel"
+	RewardItems []CodeReward `gorm:"type:jsonb;serializer:json" json:"rewardItems"`
+	Source      string       `gorm:"size:100" json:"source"`
+	IsActive    bool         `gorm:"default:true;index" json:"isActive"`
+	ExpiresAt   *time.Time   `gorm:"index" json:"expiresAt"`
+	Seeded      bool         `gorm:"not null;default:false" json:"-"`
+	CreatedAt   time.Time    `json:"createdAt"`
+}
+
+// CodeReward is one item granted by a redemption code
+type CodeReward struct {
+	Item     string `json:"item"`
+	Quantity int    `json:"quantity"`
+}
+
+var rewardPattern = regexp.MustCompile(`^([\d,]+)\s*[xX]?\s+(.+)$`)
+
+// ParseRewards turns reward text like "60 Stellar Jade + 1 Fuel" into
+// structured items. Parts without a leading quantity count as one item.
+func ParseRewards(text string) []CodeReward {
+	rewards := []CodeReward{}
+	for _, part := range strings.Split(text, "+") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		reward := CodeReward{Item: part, Quantity: 1}
+		if m := rewardPattern.FindStringSubmatch(part); m != nil {
+			if qty, err := strconv.Atoi(strings.ReplaceAll(m[1], ",", "")); err == nil {
+				reward = CodeReward{Item: strings.TrimSpace(m[2]), Quantity: qty}
+			}
+		}
+		rewards = append(rewards, reward)
+	}
+	return rewards
+}
+
+// UserRedeemedCode marks a code as redeemed by a user
+type UserRedeemedCode struct {
+	ID         int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID     uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_user_redeemed_code" json:"userId"`
+	CodeID     int       `gorm:"not null;uniqueIndex:idx_user_redeemed_code;index" json:"codeId"`
+	RedeemedAt time.Time `gorm:"autoCreateTime" json:"redeemedAt"`
+
+	// Relations
+	User User `gorm:"foreignKey:UserID" json:"-"`
+	Code Code `gorm:"foreignKey:CodeID" json:"code"`
 }
 
 // Event represents a game event
 type Event struct {
 	ID          int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	Slug        *string   `gorm:"uniqueIndex;size:50" json:"slug"` // ID from events.json, e.g. "event-chrysos"
 	Name        string    `gorm:"not null;size:200" json:"name"`
 	Type        string    `gorm:"size:50" json:"type"`
 	Description string    `gorm:"type:text" json:"description"`
+	Rewards     []string  `gorm:"type:jsonb;serializer:json" json:"rewards"`
 	StartDate   time.Time `gorm:"not null;index" json:"startDate"`
 	EndDate     time.Time `gorm:"not null;index" json:"endDate"`
 	ImageURL    string    `gorm:"size:500" json:"imageUrl"`
+	Seeded      bool      `gorm:"not null;default:false" json:"-"`
 }
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseRewards(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []CodeReward
+	}{
+		{
+			name: "several items",
+			text: "60 Stellar Jade + 1 Fuel",
+			want: []CodeReward{{Item: "Stellar Jade", Quantity: 60}, {Item: "Fuel", Quantity: 1}},
+		},
+		{
+			name: "thousands separator",
+			text: "10,000 Credits",
+			want: []CodeReward{{Item: "Credits", Quantity: 10000}},
+		},
+		{
+			name: "x before the item",
+			text: "5x Refined Aether + 3 X Traveler's Guide",
+			want: []CodeReward{{Item: "Refined Aether", Quantity: 5}, {Item: "Traveler's Guide", Quantity: 3}},
+		},
+		{
+			name: "no quantity",
+			text: "Stellar Jade",
+			want: []CodeReward{{Item: "Stellar Jade", Quantity: 1}},
+		},
+		{
+			name: "no spaces around plus",
+			text: "100 Stellar Jade+50,000 Credits",
+			want: []CodeReward{{Item: "Stellar Jade", Quantity: 100}, {Item: "Credits", Quantity: 50000}},
+		},
+		{
+			name: "quantity too large",
+			text: "99999999999999999999 Stellar Jade",
+			want: []CodeReward{{Item: "99999999999999999999 Stellar Jade", Quantity: 1}},
+		},
+		{
+			name: "separators only",
+			text: ",,, Credits",
+			want: []CodeReward{{Item: ",,, Credits", Quantity: 1}},
+		},
+		{
+			name: "empty",
+			text: "",
+			want: []CodeReward{},
+		},
+		{
+			name: "empty parts",
+			text: " + 60 Stellar Jade +  + ",
+			want: []CodeReward{{Item: "Stellar Jade", Quantity: 60}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseRewards(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseRewards(%q) = %+v, want %+v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package models
+
+import "encoding/json"
+
+// LightCone represents a light cone from the game catalog
+type LightCone struct {
+	ID     string `gorm:"primaryKey;size:50" json:"id"`
+	GameID string `gorm:"uniqueIndex;not null;size:10" json:"gameId"`
+	Name   string `gorm:"not null;size:100" json:"name"`
+	Rarity int    `gorm:"not null;default:4;index" json:"rarity"`
+	PathID int    `gorm:"not null;index" json:"pathId"`
+	MaxHP  int    `gorm:"not null" json:"maxHp"` // Level 80 stats
+	MaxAtk int    `gorm:"not null" json:"maxAtk"`
+	MaxDef int    `gorm:"not null" json:"maxDef"`
+	Seeded bool   `gorm:"not null;default:false" json:"-"`
+
+	// Relations
+	Path             Path                       `gorm:"foreignKey:PathID" json:"path,omitempty"`
+	Superimpositions []LightConeSuperimposition `gorm:"foreignKey:LightConeID" json:"superimpositions,omitempty"`
+}
+
+// LightConeSuperimposition is a light cone's passive at a superimposition rank
+type LightConeSuperimposition struct {
+	ID          int             `gorm:"primaryKey;autoIncrement" json:"id"`
+	LightConeID string          `gorm:"not null;size:50;uniqueIndex:idx_light_cone_rank" json:"lightConeId"`
+	Rank        int             `gorm:"not null;uniqueIndex:idx_light_cone_rank" json:"rank"` // 1-5
+	Description string          `gorm:"type:text" json:"description"`
+	Properties  json.RawMessage `gorm:"type:jsonb" json:"properties,omitempty"`
+
+	// Relations
+	LightCone LightCone `gorm:"foreignKey:LightConeID" json:"-"`
+}
@@ -0,0 +1,99 @@
+package models
+
+import "encoding/json"
+
+// CharacterLore contains story information for a character in the lore graph.
+// IDs match Character IDs where the character is playable, but lore may also
+// cover characters that are not in the game catalog.
+type CharacterLore struct {
+	ID        string  `gorm:"primaryKey;size:50" json:"id"`
+	Name      string  `gorm:"not null;size:100" json:"name"`
+	Title     string  `gorm:"size:100" json:"title"`
+	Bio       string  `gorm:"type:text" json:"bio"`
+	Element   string  `gorm:"size:20" json:"element"`
+	Path      string  `gorm:"size:30" json:"path"`
+	FactionID *string `gorm:"size:50;index" json:"factionId"`
+	Seeded    bool    `gorm:"not null;default:false" json:"-"`
+
+	// Relations
+	Faction           *Faction                `gorm:"foreignKey:FactionID" json:"faction,omitempty"`
+	OutgoingRelations []CharacterRelationship `gorm:"foreignKey:FromID" json:"relationships,omitempty"`
+}
+
+// CharacterRelationship is a directed relationship between two characters
+type CharacterRelationship struct {
+	ID     int    `gorm:"primaryKey;autoIncrement" json:"id"`
+	FromID string `gorm:"not null;size:50;uniqueIndex:idx_character_relationship" json:"fromId"`
+	ToID   string `gorm:"not null;size:50;uniqueIndex:idx_character_relationship;index" json:"toId"`
+	Type   string `gorm:"not null;size:20" json:"type"` // ally, enemy, family, friend, related, serves, complex
+	Label  string `gorm:"size:100" json:"label"`        // e.g. "Fellow Hunter", "Sister"
+
+	// Relations
+	From CharacterLore `gorm:"foreignKey:FromID" json:"-"`
+	To   CharacterLore `gorm:"foreignKey:ToID" json:"-"`
+}
+
+// Faction represents an organization characters belong to
+type Faction struct {
+	ID          string `gorm:"primaryKey;size:50" json:"id"`
+	Name        string `gorm:"not null;size:100" json:"name"`
+	Type        string `gorm:"size:20" json:"type"` // allies, ambiguous, neutral, location
+	Description string `gorm:"type:text" json:"description"`
+	Leader      string `gorm:"size:50" json:"leader"`
+	Color       string `gorm:"size:10" json:"color"` // Hex code
+	Icon        string `gorm:"size:20" json:"icon"`  // Emoji icon
+	Seeded      bool   `gorm:"not null;default:false" json:"-"`
+
+	// Relations
+	Members []CharacterLore `gorm:"foreignKey:FactionID" json:"members,omitempty"`
+}
+
+// Location represents a world or area in the story
+type Location struct {
+	ID          string          `gorm:"primaryKey;size:50" json:"id"`
+	Name        string          `gorm:"not null;size:100" json:"name"`
+	Type        string          `gorm:"size:30" json:"type"` // space_station, planet, flagship, dreamscape
+	Description string          `gorm:"type:text" json:"description"`
+	Areas       json.RawMessage `gorm:"type:jsonb" json:"areas,omitempty"` // Array of area names
+	Seeded      bool            `gorm:"not null;default:false" json:"-"`
+
+	// Relations
+	Connections []LocationConnection `gorm:"foreignKey:FromID" json:"connections,omitempty"`
+}
+
+// LocationConnection is a directed travel link between two locations
+type LocationConnection struct {
+	ID     int    `gorm:"primaryKey;autoIncrement" json:"id"`
+	FromID string `gorm:"not null;size:50;uniqueIndex:idx_location_connection" json:"fromId"`
+	ToID   string `gorm:"not null;size:50;uniqueIndex:idx_location_connection;index" json:"toId"`
+
+	// Relations
+	From Location `gorm:"foreignKey:FromID" json:"-"`
+	To   Location `gorm:"foreignKey:ToID" json:"-"`
+}
+
+// TimelineEvent is a story event in chronological order
+type TimelineEvent struct {
+	ID          string  `gorm:"primaryKey;size:50" json:"id"`
+	Title       string  `gorm:"not null;size:200" json:"title"`
+	Chapter     string  `gorm:"size:50" json:"chapter"` // "Prologue", "Chapter 1", etc.
+	Description string  `gorm:"type:text" json:"description"`
+	LocationID  *string `gorm:"size:50;index" json:"locationId"`
+	Order       int     `gorm:"column:sort_order;not null;default:0;index" json:"order"`
+	Seeded      bool    `gorm:"not null;default:false" json:"-"`
+
+	// Relations
+	Location   *Location           `gorm:"foreignKey:LocationID" json:"location,omitempty"`
+	Characters []TimelineCharacter `gorm:"foreignKey:EventID" json:"characters,omitempty"`
+}
+
+// TimelineCharacter links a character to a timeline event
+type TimelineCharacter struct {
+	ID          int    `gorm:"primaryKey;autoIncrement" json:"id"`
+	EventID     string `gorm:"not null;size:50;uniqueIndex:idx_timeline_character" json:"eventId"`
+	CharacterID string `gorm:"not null;size:50;uniqueIndex:idx_timeline_character;index" json:"characterId"`
+
+	// Relations
+	Event     TimelineEvent `gorm:"foreignKey:EventID" json:"-"`
+	Character CharacterLore `gorm:"foreignKey:CharacterID" json:"-"`
+}
@@ -0,0 +1,16 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// UserProfile is a cached Mihomo showcase profile for a game UID
+type UserProfile struct {
+	UID        string          `gorm:"primaryKey;size:20" json:"uid"`
+	Data       json.RawMessage `gorm:"type:jsonb;not null" json:"data"`
+	FetchedAt  time.Time       `gorm:"not null" json:"fetchedAt"`
+	ExpiresAt  time.Time       `gorm:"not null;index" json:"expiresAt"`
+	StaleUntil time.Time       `gorm:"not null" json:"staleUntil"`
+	UpdatedAt  time.Time       `json:"updatedAt"`
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Verification token types
+const (
+	TokenTypeEmail         = "email"          // Email verification link
+	TokenTypePasswordReset = "password_reset" // Password reset link
+	TokenTypeOTP           = "otp"            // Short numeric code for email verification
+)
+
+// VerificationToken is a single-use token sent to a user's email address.
+// Only a SHA-256 hash of the token is stored.
+type VerificationToken struct {
+	ID         uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID     uuid.UUID `gorm:"type:uuid;not null;index" json:"userId"`
+	Identifier string    `gorm:"not null;index" json:"identifier"` // Email address the token was sent to
+	TokenHash  string    `gorm:"not null;uniqueIndex;size:64" json:"-"`
+	Type       string    `gorm:"not null;size:20;index" json:"type"`
+	Attempts   int       `gorm:"default:0" json:"attempts"` // Failed OTP guesses
+	ExpiresAt  time.Time `gorm:"not null;index" json:"expiresAt"`
+	CreatedAt  time.Time `json:"createdAt"`
+
+	// Relations
+	User User `gorm:"foreignKey:UserID" json:"-"`
+}
+
+// RefreshToken tracks an issued refresh token by its jti. Tokens rotated
+// from the same login share a FamilyID, so reuse of an already rotated
+// token can revoke the whole family.
+type RefreshToken struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primary_key" json:"id"` // JWT ID (jti)
+	UserID     uuid.UUID  `gorm:"type:uuid;not null;index" json:"userId"`
+	FamilyID   uuid.UUID  `gorm:"type:uuid;not null;index" json:"familyId"`
+	ReplacedBy *uuid.UUID `gorm:"type:uuid" json:"replacedBy"`
+	UserAgent  string     `gorm:"size:255" json:"userAgent"`
+	IP         string     `gorm:"size:45" json:"ip"`
+	ExpiresAt  time.Time  `gorm:"not null;index" json:"expiresAt"`
+	RevokedAt  *time.Time `json:"revokedAt"`
+	CreatedAt  time.Time  `json:"createdAt"`
+
+	// Relations
+	User User `gorm:"foreignKey:UserID" json:"-"`
+}
@@ -1,12 +1,19 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+// User roles
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
 // User represents an authenticated user
 type User struct {
 	ID            uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
@@ -16,6 +23,7 @@ type User struct {
 	UID           string         `gorm:"uniqueIndex" json:"uid"`
 	Nickname      string         `gorm:"" json:"nickname"`
 	EmailVerified bool           `gorm:"default:false" json:"emailVerified"`
+	Role          string         `gorm:"size:20;not null;default:'user'" json:"role"`
 	CreatedAt     time.Time      `json:"createdAt"`
 	UpdatedAt     time.Time      `json:"updatedAt"`
 	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
@@ -26,16 +34,19 @@ type User struct {
 
 // UserCharacter represents a character owned by a user
 type UserCharacter struct {
-	ID          uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
-	UserID      uuid.UUID `gorm:"type:uuid;not null;index" json:"userId"`
-	CharacterID string    `gorm:"not null;index" json:"characterId"`
-	Eidolon     int       `gorm:"default:0" json:"eidolon"`
-	Level       int       `gorm:"default:1" json:"level"`
-	CreatedAt   time.Time `json:"createdAt"`
+	ID              uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID          uuid.UUID `gorm:"type:uuid;not null;index" json:"userId"`
+	CharacterID     string    `gorm:"not null;index" json:"characterId"`
+	Eidolon         int       `gorm:"default:0" json:"eidolon"`
+	Level           int       `gorm:"default:1" json:"level"`
+	LightConeID     *string   `gorm:"size:50;index" json:"lightConeId"`
+	Superimposition int       `gorm:"default:0" json:"superimposition"` // 1-5, 0 without a light cone
+	CreatedAt       time.Time `json:"createdAt"`
 
 	// Relations
-	User      User      `gorm:"foreignKey:UserID" json:"-"`
-	Character Character `gorm:"foreignKey:CharacterID" json:"character,omitempty"`
+	User      User       `gorm:"foreignKey:UserID" json:"-"`
+	Character Character  `gorm:"foreignKey:CharacterID" json:"character,omitempty"`
+	LightCone *LightCone `gorm:"foreignKey:LightConeID" json:"lightCone,omitempty"`
 }
 
 // Unique constraint for user-character combination
@@ -47,3 +58,113 @@ type UserCharacterUnique struct {
 	UserID      uuid.UUID `gorm:"uniqueIndex:idx_user_character"`
 	CharacterID string    `gorm:"uniqueIndex:idx_user_character"`
 }
+
+// PullPlan is a user's saved pull planner state
+type PullPlan struct {
+	ID           uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID       uuid.UUID `gorm:"type:uuid;not null;index" json:"userId"`
+	Name         string    `gorm:"size:100" json:"name"`
+	BannerType   string    `gorm:"size:20;default:'standard'" json:"bannerType"` // "standard" or "lightCone"
+	BannerID     *int      `gorm:"index" json:"bannerId"`
+	CurrentPity  int       `gorm:"default:0" json:"currentPity"`
+	IsGuaranteed bool      `gorm:"default:false" json:"isGuaranteed"`
+	StellarJade  int       `gorm:"default:0" json:"stellarJade"`
+	Passes       int       `gorm:"default:0" json:"passes"`
+	DailyIncome  int       `gorm:"default:0" json:"dailyIncome"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+
+	// Relations
+	User   User    `gorm:"foreignKey:UserID" json:"-"`
+	Banner *Banner `gorm:"foreignKey:BannerID" json:"banner,omitempty"`
+}
+
+// TeamPreset is a named team saved from the speed tuner
+type TeamPreset struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index" json:"userId"`
+	Name      string    `gorm:"not null;size:100" json:"name"`
+	BossSpeed int       `gorm:"default:0" json:"bossSpeed"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+
+	// Relations
+	User    User               `gorm:"foreignKey:UserID" json:"-"`
+	Members []TeamPresetMember `gorm:"foreignKey:PresetID;constraint:OnDelete:CASCADE" json:"members"`
+}
+
+// TeamPresetMember is a character slot in a team preset
+type TeamPresetMember struct {
+	ID           int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	PresetID     uuid.UUID `gorm:"type:uuid;not null;index" json:"presetId"`
+	Slot         int       `gorm:"not null" json:"slot"`
+	CharacterID  string    `gorm:"not null;size:50;index" json:"characterId"`
+	SpeedBonus   int       `gorm:"default:0" json:"speedBonus"`
+	SpeedPercent float64   `gorm:"type:decimal(5,2);default:0" json:"speedPercent"`
+
+	// Relations
+	Preset    TeamPreset `gorm:"foreignKey:PresetID" json:"-"`
+	Character Character  `gorm:"foreignKey:CharacterID" json:"character,omitempty"`
+}
+
+// Battle is a recorded battle simulator run
+type Battle struct {
+	ID          uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID      uuid.UUID `gorm:"type:uuid;not null;index" json:"userId"`
+	EnemyID     string    `gorm:"not null;size:50;index" json:"enemyId"`
+	Result      string    `gorm:"not null;size:20" json:"result"` // "victory", "defeat", "abandoned"
+	TotalDamage int       `gorm:"not null;index" json:"totalDamage"`
+	Turns       int       `gorm:"not null;index" json:"turns"`
+	Duration    *int      `json:"duration"` // seconds
+	Seed        *int64    `json:"seed"`
+	MaxCycles   int       `gorm:"default:0" json:"maxCycles"`
+	Validated   bool      `gorm:"default:false;index" json:"validated"`
+	Ranked      bool      `gorm:"default:false;index" json:"ranked"` // Validated with base stats only
+	ActionValue *float64  `json:"actionValue"`                       // From the replay, for validated runs
+	CreatedAt   time.Time `json:"createdAt"`
+
+	// Relations
+	User  User           `gorm:"foreignKey:UserID" json:"-"`
+	Enemy Enemy          `gorm:"foreignKey:EnemyID" json:"-"`
+	Team  []BattleMember `gorm:"foreignKey:BattleID;constraint:OnDelete:CASCADE" json:"team"`
+}
+
+// BattleMember is a character slot in a recorded battle, with the stat
+// overrides needed to replay it
+type BattleMember struct {
+	ID          int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	BattleID    uuid.UUID `gorm:"type:uuid;not null;index" json:"battleId"`
+	Slot        int       `gorm:"not null" json:"slot"`
+	CharacterID string    `gorm:"not null;size:50;index" json:"characterId"`
+	Speed       *float64  `json:"speed,omitempty"`
+	Atk         *float64  `json:"atk,omitempty"`
+	CritRate    *float64  `json:"critRate,omitempty"`
+	CritDmg     *float64  `json:"critDmg,omitempty"`
+	DmgBonus    *float64  `json:"dmgBonus,omitempty"`
+
+	// Relations
+	Battle Battle `gorm:"foreignKey:BattleID" json:"-"`
+}
+
+// Activity types recorded in the user's feed
+const (
+	ActivityLogin          = "login"
+	ActivityUIDSet         = "uid_set"
+	ActivityProfileRefresh = "profile_refresh"
+	ActivityPullPlanner    = "pull_planner"
+	ActivitySpeedTuner     = "speed_tuner"
+	ActivityBattle         = "battle"
+	ActivityRosterImport   = "roster_import"
+)
+
+// Activity is an entry in a user's activity feed
+type Activity struct {
+	ID        uuid.UUID       `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID    uuid.UUID       `gorm:"type:uuid;not null;index:idx_activity_user_created" json:"userId"`
+	Type      string          `gorm:"not null;size:50;index" json:"type"`
+	Data      json.RawMessage `gorm:"type:jsonb" json:"data,omitempty"`
+	CreatedAt time.Time       `gorm:"index:idx_activity_user_created" json:"createdAt"`
+
+	// Relations
+	User User `gorm:"foreignKey:UserID" json:"-"`
+}
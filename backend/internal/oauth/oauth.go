@@ -0,0 +1,295 @@
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hsr-tools/backend/internal/config"
+)
+
+// ErrUnknownProvider is returned for provider names that aren't configured
+var ErrUnknownProvider = errors.New("unknown OAuth provider")
+
+// Providers holds the configured login providers, set up by Setup
+var Providers = map[string]*Provider{}
+
+// FrontendURL is where the callback sends the browser back to
+var FrontendURL = "http://localhost:3000"
+
+// Setup registers the providers from the config
+func Setup(cfg *config.Config) {
+	FrontendURL = strings.TrimRight(cfg.FrontendURL, "/")
+	Providers = make(map[string]*Provider, len(cfg.OAuthProviders))
+	for _, p := range cfg.OAuthProviders {
+		Providers[p.Name] = NewProvider(p, strings.TrimRight(cfg.OAuthCallbackURL, "/")+"/"+p.Name+"/callback")
+	}
+}
+
+// Get returns a configured provider by name
+func Get(name string) (*Provider, error) {
+	p, ok := Providers[name]
+	if !ok {
+		return nil, ErrUnknownProvider
+	}
+	return p, nil
+}
+
+// Names lists the configured provider names in order
+func Names() []string {
+	names := make([]string, 0, len(Providers))
+	for name := range Providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Token is a provider token response
+type Token struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+	Scope        string `json:"scope"`
+	IDToken      string `json:"id_token"`
+}
+
+// UserInfo is the identity returned by a provider
+type UserInfo struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+type endpoints struct {
+	AuthURL     string `json:"authorization_endpoint"`
+	TokenURL    string `json:"token_endpoint"`
+	UserInfoURL string `json:"userinfo_endpoint"`
+}
+
+// Provider runs the authorization code flow with PKCE against one provider
+type Provider struct {
+	Name        string
+	RedirectURL string
+	HTTPClient  *http.Client
+
+	cfg config.OAuthProvider
+
+	mu        sync.Mutex
+	endpoints *endpoints
+}
+
+func NewProvider(cfg config.OAuthProvider, redirectURL string) *Provider {
+	return &Provider{
+		Name:        cfg.Name,
+		RedirectURL: redirectURL,
+		HTTPClient:  &http.Client{Timeout: 10 * time.Second},
+		cfg:         cfg,
+	}
+}
+
+// IsOIDC reports whether the provider is an OpenID Connect issuer
+func (p *Provider) IsOIDC() bool {
+	return p.cfg.IssuerURL != ""
+}
+
+// resolve returns the provider endpoints, running OIDC discovery the first
+// time so startup doesn't depend on the provider being reachable
+func (p *Provider) resolve(ctx context.Context) (*endpoints, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.endpoints != nil {
+		return p.endpoints, nil
+	}
+
+	ep := &endpoints{AuthURL: p.cfg.AuthURL, TokenURL: p.cfg.TokenURL, UserInfoURL: p.cfg.UserInfoURL}
+	if p.IsOIDC() {
+		var discovered endpoints
+		if err := p.getJSON(ctx, strings.TrimRight(p.cfg.IssuerURL, "/")+"/.well-known/openid-configuration", "", &discovered); err != nil {
+			return nil, fmt.Errorf("OIDC discovery failed: %w", err)
+		}
+		// Explicit endpoints win over discovered ones
+		if ep.AuthURL == "" {
+			ep.AuthURL = discovered.AuthURL
+		}
+		if ep.TokenURL == "" {
+			ep.TokenURL = discovered.TokenURL
+		}
+		if ep.UserInfoURL == "" {
+			ep.UserInfoURL = discovered.UserInfoURL
+		}
+	}
+	if ep.AuthURL == "" || ep.TokenURL == "" || ep.UserInfoURL == "" {
+		return nil, fmt.Errorf("provider %s is missing endpoints", p.Name)
+	}
+
+	p.endpoints = ep
+	return ep, nil
+}
+
+// NewVerifier returns a random PKCE code verifier
+func NewVerifier() string {
+	return randomString(32)
+}
+
+// NewState returns a random state value
+func NewState() string {
+	return randomString(24)
+}
+
+func randomString(n int) string {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+// Challenge derives the S256 PKCE code challenge for a verifier
+func Challenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// AuthCodeURL builds the URL the user is sent to for login
+func (p *Provider) AuthCodeURL(ctx context.Context, state, verifier string) (string, error) {
+	ep, err := p.resolve(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	params := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.cfg.ClientID},
+		"redirect_uri":          {p.RedirectURL},
+		"scope":                 {strings.Join(p.cfg.Scopes, " ")},
+		"state":                 {state},
+		"code_challenge":        {Challenge(verifier)},
+		"code_challenge_method": {"S256"},
+	}
+
+	sep := "?"
+	if strings.Contains(ep.AuthURL, "?") {
+		sep = "&"
+	}
+	return ep.AuthURL + sep + params.Encode(), nil
+}
+
+// Exchange trades an authorization code for tokens
+func (p *Provider) Exchange(ctx context.Context, code, verifier string) (*Token, error) {
+	ep, err := p.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.RedirectURL},
+		"client_id":     {p.cfg.ClientID},
+		"client_secret": {p.cfg.ClientSecret},
+		"code_verifier": {verifier},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ep.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	var token Token
+	if err := p.do(req, &token); err != nil {
+		return nil, fmt.Errorf("token exchange failed: %w", err)
+	}
+	if token.AccessToken == "" {
+		return nil, errors.New("token exchange failed: no access token in response")
+	}
+	return &token, nil
+}
+
+// UserInfo fetches the user's identity with an access token. Plain OAuth2
+// providers use different field names, so the common variants are accepted.
+func (p *Provider) UserInfo(ctx context.Context, accessToken string) (*UserInfo, error) {
+	ep, err := p.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw map[string]interface{}
+	if err := p.getJSON(ctx, ep.UserInfoURL, accessToken, &raw); err != nil {
+		return nil, fmt.Errorf("userinfo request failed: %w", err)
+	}
+
+	info := &UserInfo{
+		Subject: firstString(raw, "sub", "id"),
+		Email:   firstString(raw, "email"),
+		Name:    firstString(raw, "name", "preferred_username", "username", "login"),
+	}
+	switch v := raw["email_verified"].(type) {
+	case bool:
+		info.EmailVerified = v
+	case string:
+		info.EmailVerified = v == "true"
+	}
+	if info.Subject == "" {
+		return nil, errors.New("userinfo response has no subject")
+	}
+	return info, nil
+}
+
+func firstString(raw map[string]interface{}, keys ...string) string {
+	for _, key := range keys {
+		switch v := raw[key].(type) {
+		case string:
+			if v != "" {
+				return v
+			}
+		case float64:
+			return fmt.Sprintf("%.0f", v)
+		}
+	}
+	return ""
+}
+
+func (p *Provider) getJSON(ctx context.Context, endpoint, accessToken string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+	return p.do(req, v)
+}
+
+func (p *Provider) do(req *http.Request, v interface{}) error {
+	resp, err := p.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned status %d", req.URL.Host, resp.StatusCode)
+	}
+	return json.Unmarshal(body, v)
+}
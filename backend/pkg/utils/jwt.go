@@ -9,9 +9,23 @@ import (
 	"github.com/google/uuid"
 )
 
+// Token types carried in the "typ" claim
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
+)
+
+// Access tokens keep the old 24h lifetime until the frontend refreshes them
+// through /auth/refresh; deployments can shorten it with ACCESS_TOKEN_TTL
+const (
+	defaultAccessTokenTTL  = 24 * time.Hour
+	defaultRefreshTokenTTL = 7 * 24 * time.Hour
+)
+
 type Claims struct {
 	UserID uuid.UUID `json:"userId"`
 	Email  string    `json:"email"`
+	Type   string    `json:"typ"`
 	jwt.RegisteredClaims
 }
 
@@ -23,34 +37,50 @@ func getJWTSecret() []byte {
 	return []byte(secret)
 }
 
-func GenerateToken(userID uuid.UUID, email string) (string, error) {
-	claims := &Claims{
-		UserID: userID,
-		Email:  email,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
-		},
+func getTTL(key string, defaultValue time.Duration) time.Duration {
+	if value, err := time.ParseDuration(os.Getenv(key)); err == nil && value > 0 {
+		return value
 	}
+	return defaultValue
+}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(getJWTSecret())
+// RefreshTokenTTL is how long a refresh token stays valid
+func RefreshTokenTTL() time.Duration {
+	return getTTL("REFRESH_TOKEN_TTL", defaultRefreshTokenTTL)
 }
 
-func GenerateRefreshToken(userID uuid.UUID, email string) (string, error) {
+func generate(userID uuid.UUID, email, tokenType string, ttl time.Duration) (string, *Claims, error) {
+	now := time.Now()
 	claims := &Claims{
 		UserID: userID,
 		Email:  email,
+		Type:   tokenType,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(7 * 24 * time.Hour)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
+			ID:        uuid.NewString(),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
 		},
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(getJWTSecret())
+	signed, err := token.SignedString(getJWTSecret())
+	if err != nil {
+		return "", nil, err
+	}
+	return signed, claims, nil
+}
+
+// GenerateToken creates a short-lived access token
+func GenerateToken(userID uuid.UUID, email string) (string, error) {
+	token, _, err := generate(userID, email, TokenTypeAccess, getTTL("ACCESS_TOKEN_TTL", defaultAccessTokenTTL))
+	return token, err
+}
+
+// GenerateRefreshToken creates a refresh token. The returned claims carry
+// the jti and expiry so the token can be persisted for rotation.
+func GenerateRefreshToken(userID uuid.UUID, email string) (string, *Claims, error) {
+	return generate(userID, email, TokenTypeRefresh, RefreshTokenTTL())
 }
 
 func ValidateToken(tokenString string) (*Claims, error) {
@@ -71,3 +101,25 @@ func ValidateToken(tokenString string) (*Claims, error) {
 
 	return nil, errors.New("invalid token")
 }
+
+func validateType(tokenString, tokenType string) (*Claims, error) {
+	claims, err := ValidateToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Type != tokenType || claims.ID == "" {
+		return nil, errors.New("wrong token type")
+	}
+	return claims, nil
+}
+
+// ValidateAccessToken only accepts access tokens, so refresh tokens can't be
+// used as bearer tokens
+func ValidateAccessToken(tokenString string) (*Claims, error) {
+	return validateType(tokenString, TokenTypeAccess)
+}
+
+// ValidateRefreshToken only accepts refresh tokens
+func ValidateRefreshToken(tokenString string) (*Claims, error) {
+	return validateType(tokenString, TokenTypeRefresh)
+}